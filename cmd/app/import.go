@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/config"
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/importer"
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/services"
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/storage/postgresql"
+)
+
+// runImport implements the "import" subcommand:
+//
+//	server import -file bookings.csv [-format csv|ics] [-dry-run]
+//
+// It prints the per-row report as JSON and fails when any row was rejected.
+func runImport(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	file := flags.String("file", "", "path to a CSV or ICS file")
+	format := flags.String("format", "", "input format: csv or ics (default: from the file extension)")
+	dryRun := flags.Bool("dry-run", false, "validate rows without writing reservations")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *file == "" {
+		return fmt.Errorf("-file is required")
+	}
+	if *format == "" {
+		*format = strings.TrimPrefix(strings.ToLower(filepath.Ext(*file)), ".")
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	rows, err := importer.Parse(f, *format)
+	if err != nil {
+		return err
+	}
+
+	env := config.MustLoad()
+	db := postgresql.NewPool(env)
+	defer postgresql.Stop(db)
+
+	service := services.NewReservationService(postgresql.NewStorage(db), 10*time.Second)
+	report, err := service.Import(ctx, rows, *dryRun)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return err
+	}
+
+	if report.Conflicts > 0 || report.Invalid > 0 {
+		return fmt.Errorf("%d rows accepted, %d conflicts, %d invalid", report.Accepted, report.Conflicts, report.Invalid)
+	}
+	return nil
+}
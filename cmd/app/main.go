@@ -16,6 +16,13 @@ func main() {
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		if err := runImport(ctx, os.Args[2:]); err != nil {
+			log.Fatalf("Import failed: %v", err)
+		}
+		return
+	}
+
 	myApp, err := app.NewApp(ctx)
 	if err != nil {
 		log.Fatalf("Failed to create the app: %v", err)
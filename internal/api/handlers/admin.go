@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+)
+
+// AdminOnly rejects requests that do not carry "Authorization: Bearer <token>".
+// An empty token disables the protected routes entirely.
+func AdminOnly(token string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !isAdmin(r, token) {
+				writeProblem(w, newProblem(r, http.StatusUnauthorized, models.ErrUnauthorized))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// isAdmin reports whether the request carries the admin token.
+func isAdmin(r *http.Request, token string) bool {
+	provided := r.Header.Get("Authorization")
+	expected := "Bearer " + token
+	return token != "" && subtle.ConstantTimeCompare([]byte(provided), []byte(expected)) == 1
+}
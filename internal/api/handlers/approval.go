@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+	"github.com/go-chi/chi/v5"
+)
+
+func (h *ReservationHandler) GetRoom(w http.ResponseWriter, r *http.Request) {
+	room, err := h.ReservationService.GetRoom(r.Context(), chi.URLParam(r, "room_id"))
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK) //200
+	json.NewEncoder(w).Encode(room)
+}
+
+// UpdateRoom changes the settings of a room. Fields missing from the body
+// keep their current value; attributes are replaced as a whole.
+func (h *ReservationHandler) UpdateRoom(w http.ResponseWriter, r *http.Request) {
+	roomID := chi.URLParam(r, "room_id")
+	room, err := h.ReservationService.GetRoom(r.Context(), roomID)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	attributes := room.Attributes
+	room.Attributes = nil
+	if err := json.NewDecoder(r.Body).Decode(room); err != nil {
+		writeProblem(w, newProblem(r, http.StatusBadRequest, errInvalidBody))
+		return
+	}
+	room.ID = roomID
+	if room.Attributes == nil {
+		room.Attributes = attributes
+	}
+
+	if err := h.ReservationService.UpdateRoom(r.Context(), room); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK) //200
+	json.NewEncoder(w).Encode(room)
+}
+
+// ListPending returns the reservations awaiting approval, of one room when
+// room_id is given.
+func (h *ReservationHandler) ListPending(w http.ResponseWriter, r *http.Request) {
+	reservations, err := h.ReservationService.ListPending(r.Context(), r.URL.Query().Get("room_id"))
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK) //200
+	json.NewEncoder(w).Encode(reservations)
+}
+
+func (h *ReservationHandler) ApproveReservation(w http.ResponseWriter, r *http.Request) {
+	h.decide(w, r, h.ReservationService.ApproveReservation)
+}
+
+func (h *ReservationHandler) RejectReservation(w http.ResponseWriter, r *http.Request) {
+	h.decide(w, r, h.ReservationService.RejectReservation)
+}
+
+func (h *ReservationHandler) decide(w http.ResponseWriter, r *http.Request, decide func(ctx context.Context, id int) (*models.Reservation, error)) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.handleError(w, r, models.ErrNoMatchingReservation)
+		return
+	}
+
+	reservation, err := decide(r.Context(), id)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", reservationETag(reservation.Version))
+	w.WriteHeader(http.StatusOK) //200
+	json.NewEncoder(w).Encode(reservation)
+}
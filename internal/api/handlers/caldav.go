@@ -0,0 +1,596 @@
+package handlers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/ical"
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/importer"
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	davNamespace    = "DAV:"
+	caldavNamespace = "urn:ietf:params:xml:ns:caldav"
+	// bookingNamespace holds the preconditions CalDAV has no element for.
+	bookingNamespace = "urn:meeting-room-booking"
+
+	xmlContentType = "application/xml; charset=utf-8"
+
+	// caldavHistory is how far back a calendar collection lists reservations
+	// when the client does not ask for a time range.
+	caldavHistory = 30 * 24 * time.Hour
+
+	// maxCalendarObjectSize limits the body of a PUT.
+	maxCalendarObjectSize = 1 << 20
+)
+
+// CalDAVHandler serves every room as a CalDAV calendar collection at
+// /caldav/rooms/{room_id}/ with one calendar object resource per
+// reservation, named after the event UID.
+type CalDAVHandler struct {
+	ReservationService models.ReservationService
+	Location           *time.Location
+}
+
+func NewCalDAVHandler(service models.ReservationService, loc *time.Location) *CalDAVHandler {
+	return &CalDAVHandler{
+		ReservationService: service,
+		Location:           loc,
+	}
+}
+
+func (h *CalDAVHandler) Options(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("DAV", "1, calendar-access")
+	w.Header().Set("Allow", "OPTIONS, GET, PUT, DELETE, PROPFIND, REPORT")
+	w.WriteHeader(http.StatusOK) //200
+}
+
+// PropfindCalendar describes a room calendar and, unless Depth is 0, the
+// calendar objects in it. The requested property names are not inspected,
+// the same fixed set of properties is always returned.
+func (h *CalDAVHandler) PropfindCalendar(w http.ResponseWriter, r *http.Request) {
+	roomID := chi.URLParam(r, "room_id")
+
+	reservations, err := h.reservations(r.Context(), roomID, time.Now().Add(-caldavHistory), time.Time{})
+	if err != nil {
+		writeDAVStatus(w, http.StatusInternalServerError)
+		return
+	}
+
+	ms := newMultistatus(davResponse{
+		Href: calendarHref(roomID),
+		Propstat: &davPropstat{
+			Prop: davProp{
+				ResourceType:        &davResourceType{Collection: &struct{}{}, Calendar: &struct{}{}},
+				DisplayName:         "Room " + roomID,
+				SupportedComponents: &davComponentSet{Components: []davComponent{{Name: "VEVENT"}}},
+				CTag:                calendarCTag(reservations),
+			},
+			Status: davStatusLine(http.StatusOK),
+		},
+	})
+	if r.Header.Get("Depth") != "0" {
+		for _, reservation := range reservations {
+			ms.Responses = append(ms.Responses, h.objectResponse(reservation, false))
+		}
+	}
+
+	writeMultistatus(w, ms)
+}
+
+func (h *CalDAVHandler) PropfindObject(w http.ResponseWriter, r *http.Request) {
+	reservation, err := h.reservationFromObject(r)
+	if err != nil {
+		writeDAVStatus(w, davErrorStatus(err))
+		return
+	}
+
+	writeMultistatus(w, newMultistatus(h.objectResponse(*reservation, false)))
+}
+
+// Report answers calendar-query, calendar-multiget and free-busy-query
+// reports on a room calendar.
+func (h *CalDAVHandler) Report(w http.ResponseWriter, r *http.Request) {
+	roomID := chi.URLParam(r, "room_id")
+
+	report, err := parseCalendarReport(io.LimitReader(r.Body, maxCalendarObjectSize))
+	if err != nil {
+		writeDAVStatus(w, http.StatusBadRequest)
+		return
+	}
+
+	switch report.Name {
+	case xml.Name{Space: caldavNamespace, Local: "calendar-query"}:
+		from := report.Start
+		if from.IsZero() {
+			from = time.Now().Add(-caldavHistory)
+		}
+		reservations, err := h.reservations(r.Context(), roomID, from, report.End)
+		if err != nil {
+			writeDAVStatus(w, davErrorStatus(err))
+			return
+		}
+
+		ms := newMultistatus()
+		for _, reservation := range reservations {
+			ms.Responses = append(ms.Responses, h.objectResponse(reservation, true))
+		}
+		writeMultistatus(w, ms)
+
+	case xml.Name{Space: caldavNamespace, Local: "calendar-multiget"}:
+		ms := newMultistatus()
+		for _, href := range report.Hrefs {
+			uid, ok := objectUID(href)
+			var reservation *models.Reservation
+			if ok {
+				reservation, err = h.lookup(r.Context(), roomID, uid)
+			}
+			switch {
+			case !ok, errors.Is(err, models.ErrNoMatchingReservation):
+				ms.Responses = append(ms.Responses, davResponse{Href: href, Status: davStatusLine(http.StatusNotFound)})
+			case err != nil:
+				writeDAVStatus(w, http.StatusInternalServerError)
+				return
+			default:
+				ms.Responses = append(ms.Responses, h.objectResponse(*reservation, true))
+			}
+		}
+		writeMultistatus(w, ms)
+
+	case xml.Name{Space: caldavNamespace, Local: "free-busy-query"}:
+		if report.Start.IsZero() || report.End.IsZero() {
+			writeDAVStatus(w, http.StatusBadRequest)
+			return
+		}
+		freeBusy, err := h.ReservationService.GetFreeBusy(r.Context(), models.FreeBusyQuery{
+			RoomIDs:   []string{roomID},
+			StartTime: report.Start,
+			EndTime:   report.End,
+		})
+		if err != nil {
+			writeDAVStatus(w, davErrorStatus(err))
+			return
+		}
+
+		w.Header().Set("Content-Type", calendarContentType)
+		w.WriteHeader(http.StatusOK) //200
+		writer := ical.NewWriter(w, time.UTC)
+		writer.BeginObject(report.Start, report.End)
+		writer.WriteFreeBusy("", time.Now(), report.Start, report.End, busyPeriods(freeBusy.Rooms[0].Busy))
+		writer.End()
+
+	default:
+		writeDAVError(w, http.StatusForbidden, davNamespace, "supported-report", nil)
+	}
+}
+
+func (h *CalDAVHandler) GetObject(w http.ResponseWriter, r *http.Request) {
+	reservation, err := h.reservationFromObject(r)
+	if err != nil {
+		writeDAVStatus(w, davErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", calendarContentType)
+	w.Header().Set("ETag", reservationETag(reservation.Version))
+	w.WriteHeader(http.StatusOK) //200
+	io.WriteString(w, h.calendarObject(*reservation))
+}
+
+// PutObject creates or moves a reservation from a calendar object holding a
+// single VEVENT. The resource must be named after the event UID, so the
+// object can be found again at the same URL. Writes go through
+// ReservationService and are checked for overlaps like any other booking.
+func (h *CalDAVHandler) PutObject(w http.ResponseWriter, r *http.Request) {
+	roomID := chi.URLParam(r, "room_id")
+
+	events, err := ical.ReadEvents(io.LimitReader(r.Body, maxCalendarObjectSize))
+	if err != nil || len(events) != 1 {
+		writeDAVError(w, http.StatusForbidden, caldavNamespace, "valid-calendar-data", nil)
+		return
+	}
+	event := events[0]
+
+	uid := ical.Unescape(event.Get("UID"))
+	if name, ok := objectUID(r.URL.EscapedPath()); !ok || uid == "" || name != uid {
+		writeDAVError(w, http.StatusForbidden, caldavNamespace, "valid-calendar-object-resource", nil)
+		return
+	}
+
+	start, err := event.Time("DTSTART")
+	if err != nil {
+		writeDAVError(w, http.StatusForbidden, caldavNamespace, "valid-calendar-data", nil)
+		return
+	}
+	end, err := event.Time("DTEND")
+	if err != nil {
+		writeDAVError(w, http.StatusForbidden, caldavNamespace, "valid-calendar-data", nil)
+		return
+	}
+
+	existing, err := h.lookup(r.Context(), roomID, uid)
+	switch {
+	case err == nil:
+		if r.Header.Get("If-None-Match") == "*" {
+			writeDAVStatus(w, http.StatusPreconditionFailed)
+			return
+		}
+		version := 0
+		if r.Header.Get("If-Match") != "" {
+			version, err = ifMatchVersion(r)
+			if err != nil {
+				writeDAVStatus(w, http.StatusPreconditionFailed)
+				return
+			}
+		}
+
+		reservation := &models.Reservation{
+			ID:        existing.ID,
+			RoomID:    roomID,
+			StartTime: start,
+			EndTime:   end,
+			Version:   version,
+		}
+		if err := h.ReservationService.Update(r.Context(), reservation); err != nil {
+			h.writeWriteError(w, err)
+			return
+		}
+
+		w.Header().Set("ETag", reservationETag(reservation.Version))
+		w.WriteHeader(http.StatusNoContent) //204
+
+	case errors.Is(err, models.ErrNoMatchingReservation):
+		if r.Header.Get("If-Match") != "" {
+			writeDAVStatus(w, http.StatusPreconditionFailed)
+			return
+		}
+
+		reservation := &models.Reservation{
+			RoomID:    roomID,
+			UserID:    importer.EventUser(event),
+			StartTime: start,
+			EndTime:   end,
+		}
+		if _, ok := ical.ParseReservationUID(uid); !ok {
+			reservation.ICalUID = uid
+		}
+		if err := h.ReservationService.Create(r.Context(), reservation); err != nil {
+			h.writeWriteError(w, err)
+			return
+		}
+
+		w.Header().Set("ETag", reservationETag(reservation.Version))
+		w.WriteHeader(http.StatusCreated) //201
+
+	default:
+		writeDAVStatus(w, http.StatusInternalServerError)
+	}
+}
+
+func (h *CalDAVHandler) DeleteObject(w http.ResponseWriter, r *http.Request) {
+	reservation, err := h.reservationFromObject(r)
+	if err != nil {
+		writeDAVStatus(w, davErrorStatus(err))
+		return
+	}
+
+	version := 0
+	if r.Header.Get("If-Match") != "" {
+		version, err = ifMatchVersion(r)
+		if err != nil {
+			writeDAVStatus(w, http.StatusPreconditionFailed)
+			return
+		}
+	}
+	reservation.Version = version
+
+	if err := h.ReservationService.DeleteByID(r.Context(), reservation); err != nil {
+		writeDAVStatus(w, davErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent) //204
+}
+
+// writeWriteError reports a failed PUT. Overlaps are reported as a
+// precondition listing the conflicting calendar objects.
+func (h *CalDAVHandler) writeWriteError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, models.ErrRoomAlreadyReservated):
+		var hrefs []string
+		var conflictErr *models.ConflictError
+		if errors.As(err, &conflictErr) {
+			for _, conflict := range conflictErr.Conflicts {
+				hrefs = append(hrefs, objectHref(conflict))
+			}
+		}
+		writeDAVError(w, http.StatusConflict, bookingNamespace, "no-reservation-conflict", hrefs)
+	case errors.Is(err, models.ErrTimeNotProvided),
+		errors.Is(err, models.ErrPastTime),
+		errors.Is(err, models.ErrEndTimeBeforeStartTime),
+		errors.Is(err, models.ErrReservationTimeExceedingLimit):
+		writeDAVError(w, http.StatusForbidden, caldavNamespace, "valid-calendar-data", nil)
+	default:
+		writeDAVStatus(w, davErrorStatus(err))
+	}
+}
+
+// reservations returns every active reservation of a room overlapping
+// [from, to), following the listing cursor across pages.
+func (h *CalDAVHandler) reservations(ctx context.Context, roomID string, from, to time.Time) ([]models.Reservation, error) {
+	filter := models.ReservationFilter{From: from, To: to}
+
+	var reservations []models.Reservation
+	for {
+		page, err := h.ReservationService.GetByRoomID(ctx, roomID, filter)
+		if err != nil {
+			return nil, err
+		}
+		reservations = append(reservations, page.Reservations...)
+		if page.NextCursor == "" {
+			return reservations, nil
+		}
+
+		filter.After, err = models.ParseCursor(page.NextCursor)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// reservationFromObject loads the reservation addressed by the room_id and
+// object URL parameters.
+func (h *CalDAVHandler) reservationFromObject(r *http.Request) (*models.Reservation, error) {
+	uid, ok := objectUID(r.URL.EscapedPath())
+	if !ok {
+		return nil, models.ErrNoMatchingReservation
+	}
+	return h.lookup(r.Context(), chi.URLParam(r, "room_id"), uid)
+}
+
+// lookup finds a reservation of a room by the UID of its event.
+func (h *CalDAVHandler) lookup(ctx context.Context, roomID, uid string) (*models.Reservation, error) {
+	if id, ok := ical.ParseReservationUID(uid); ok {
+		reservation, err := h.ReservationService.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if reservation.RoomID != roomID || reservation.ICalUID != "" {
+			return nil, models.ErrNoMatchingReservation
+		}
+		return reservation, nil
+	}
+
+	return h.ReservationService.GetByICalUID(ctx, roomID, uid)
+}
+
+func (h *CalDAVHandler) objectResponse(reservation models.Reservation, withData bool) davResponse {
+	prop := davProp{
+		GetETag:        reservationETag(reservation.Version),
+		GetContentType: "text/calendar; component=vevent",
+	}
+	if withData {
+		prop.CalendarData = h.calendarObject(reservation)
+	}
+
+	return davResponse{
+		Href:     objectHref(reservation),
+		Propstat: &davPropstat{Prop: prop, Status: davStatusLine(http.StatusOK)},
+	}
+}
+
+func (h *CalDAVHandler) calendarObject(reservation models.Reservation) string {
+	var b strings.Builder
+	writer := ical.NewWriter(&b, h.Location)
+	writer.BeginObject(reservation.StartTime, reservation.EndTime)
+	writer.WriteEvent(ical.ReservationEvent(reservation))
+	writer.End()
+	return b.String()
+}
+
+func calendarHref(roomID string) string {
+	return "/caldav/rooms/" + url.PathEscape(roomID) + "/"
+}
+
+func objectHref(reservation models.Reservation) string {
+	return calendarHref(reservation.RoomID) + url.PathEscape(ical.ReservationUID(reservation)) + ".ics"
+}
+
+// objectUID returns the event UID a calendar object path or href is named
+// after.
+func objectUID(href string) (string, bool) {
+	if u, err := url.Parse(href); err == nil {
+		href = u.EscapedPath()
+	}
+	name, ok := strings.CutSuffix(path.Base(href), ".ics")
+	if !ok || name == "" {
+		return "", false
+	}
+	uid, err := url.PathUnescape(name)
+	if err != nil {
+		return "", false
+	}
+	return uid, true
+}
+
+// calendarCTag changes whenever a reservation of the calendar is added,
+// changed or removed, so clients know when to resynchronise.
+func calendarCTag(reservations []models.Reservation) string {
+	hash := sha256.New()
+	for _, reservation := range reservations {
+		fmt.Fprintf(hash, "%d:%d,", reservation.ID, reservation.Version)
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:32]
+}
+
+func davErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrNoMatchingReservation):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrVersionMismatch):
+		return http.StatusPreconditionFailed
+	case errors.Is(err, models.ErrInvalidParameter),
+		errors.Is(err, models.ErrTimeNotProvided),
+		errors.Is(err, models.ErrEndTimeBeforeStartTime):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// calendarReport is the part of a REPORT body the handler understands.
+type calendarReport struct {
+	Name  xml.Name
+	Start time.Time
+	End   time.Time
+	Hrefs []string
+}
+
+// parseCalendarReport reads the report type, the first time-range and the
+// hrefs of a REPORT body. Other filters are ignored.
+func parseCalendarReport(r io.Reader) (*calendarReport, error) {
+	var report calendarReport
+	decoder := xml.NewDecoder(r)
+	inHref := false
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch {
+			case report.Name.Local == "":
+				report.Name = t.Name
+			case t.Name == xml.Name{Space: davNamespace, Local: "href"}:
+				inHref = true
+			case t.Name == xml.Name{Space: caldavNamespace, Local: "time-range"} && report.Start.IsZero() && report.End.IsZero():
+				for _, attr := range t.Attr {
+					var dst *time.Time
+					switch attr.Name.Local {
+					case "start":
+						dst = &report.Start
+					case "end":
+						dst = &report.End
+					default:
+						continue
+					}
+					if *dst, err = ical.ParseUTC(attr.Value); err != nil {
+						return nil, err
+					}
+				}
+			}
+		case xml.EndElement:
+			inHref = false
+		case xml.CharData:
+			if inHref {
+				report.Hrefs = append(report.Hrefs, strings.TrimSpace(string(t)))
+			}
+		}
+	}
+
+	if report.Name.Local == "" {
+		return nil, io.ErrUnexpectedEOF
+	}
+	return &report, nil
+}
+
+type davMultistatus struct {
+	XMLName   xml.Name      `xml:"D:multistatus"`
+	DAV       string        `xml:"xmlns:D,attr"`
+	CalDAV    string        `xml:"xmlns:C,attr"`
+	CS        string        `xml:"xmlns:CS,attr"`
+	Responses []davResponse `xml:"D:response"`
+}
+
+type davResponse struct {
+	Href     string       `xml:"D:href"`
+	Propstat *davPropstat `xml:"D:propstat,omitempty"`
+	Status   string       `xml:"D:status,omitempty"`
+}
+
+type davPropstat struct {
+	Prop   davProp `xml:"D:prop"`
+	Status string  `xml:"D:status"`
+}
+
+type davProp struct {
+	ResourceType        *davResourceType `xml:"D:resourcetype,omitempty"`
+	DisplayName         string           `xml:"D:displayname,omitempty"`
+	GetETag             string           `xml:"D:getetag,omitempty"`
+	GetContentType      string           `xml:"D:getcontenttype,omitempty"`
+	CTag                string           `xml:"CS:getctag,omitempty"`
+	SupportedComponents *davComponentSet `xml:"C:supported-calendar-component-set,omitempty"`
+	CalendarData        string           `xml:"C:calendar-data,omitempty"`
+}
+
+type davResourceType struct {
+	Collection *struct{} `xml:"D:collection,omitempty"`
+	Calendar   *struct{} `xml:"C:calendar,omitempty"`
+}
+
+type davComponentSet struct {
+	Components []davComponent `xml:"C:comp"`
+}
+
+type davComponent struct {
+	Name string `xml:"name,attr"`
+}
+
+func newMultistatus(responses ...davResponse) *davMultistatus {
+	return &davMultistatus{
+		DAV:       davNamespace,
+		CalDAV:    caldavNamespace,
+		CS:        "http://calendarserver.org/ns/",
+		Responses: responses,
+	}
+}
+
+func writeMultistatus(w http.ResponseWriter, ms *davMultistatus) {
+	w.Header().Set("Content-Type", xmlContentType)
+	w.WriteHeader(http.StatusMultiStatus) //207
+	io.WriteString(w, xml.Header)
+	xml.NewEncoder(w).Encode(ms)
+}
+
+func davStatusLine(status int) string {
+	return fmt.Sprintf("HTTP/1.1 %d %s", status, http.StatusText(status))
+}
+
+func writeDAVStatus(w http.ResponseWriter, status int) {
+	w.WriteHeader(status)
+}
+
+// writeDAVError writes a DAV:error body naming the failed precondition,
+// optionally with the hrefs of the resources that caused it.
+func writeDAVError(w http.ResponseWriter, status int, namespace, condition string, hrefs []string) {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	fmt.Fprintf(&b, `<D:error xmlns:D="DAV:"><E:%s xmlns:E="%s">`, condition, namespace)
+	for _, href := range hrefs {
+		b.WriteString("<D:href>")
+		xml.EscapeText(&b, []byte(href))
+		b.WriteString("</D:href>")
+	}
+	fmt.Fprintf(&b, "</E:%s></D:error>\n", condition)
+
+	w.Header().Set("Content-Type", xmlContentType)
+	w.WriteHeader(status)
+	io.WriteString(w, b.String())
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/ical"
+	"github.com/go-chi/chi/v5"
+)
+
+const calendarContentType = "text/calendar; charset=utf-8"
+
+type CalendarHandler struct {
+	ReservationService models.ReservationService
+	FeedSecret         string
+	Location           *time.Location
+}
+
+func NewCalendarHandler(service models.ReservationService, feedSecret string, loc *time.Location) *CalendarHandler {
+	return &CalendarHandler{
+		ReservationService: service,
+		FeedSecret:         feedSecret,
+		Location:           loc,
+	}
+}
+
+func (h *CalendarHandler) RoomFeed(w http.ResponseWriter, r *http.Request) {
+	h.serveFeed(w, r, models.CalendarFeed{Kind: models.RoomFeed, Subject: chi.URLParam(r, "room_id")})
+}
+
+func (h *CalendarHandler) UserFeed(w http.ResponseWriter, r *http.Request) {
+	h.serveFeed(w, r, models.CalendarFeed{Kind: models.UserFeed, Subject: chi.URLParam(r, "user_id")})
+}
+
+// RoomFeedURL returns the secret subscription URL of a room feed.
+func (h *CalendarHandler) RoomFeedURL(w http.ResponseWriter, r *http.Request) {
+	h.serveFeedURL(w, r, models.CalendarFeed{Kind: models.RoomFeed, Subject: chi.URLParam(r, "room_id")})
+}
+
+// UserFeedURL returns the secret subscription URL of a user feed.
+func (h *CalendarHandler) UserFeedURL(w http.ResponseWriter, r *http.Request) {
+	h.serveFeedURL(w, r, models.CalendarFeed{Kind: models.UserFeed, Subject: chi.URLParam(r, "user_id")})
+}
+
+func (h *CalendarHandler) serveFeed(w http.ResponseWriter, r *http.Request, feed models.CalendarFeed) {
+	if !validFeedToken(h.FeedSecret, feed, r.URL.Query().Get("token")) {
+		writeProblem(w, newProblem(r, http.StatusForbidden, models.ErrInvalidFeedToken))
+		return
+	}
+
+	reservations, err := h.ReservationService.GetCalendar(r.Context(), feed)
+	if err != nil {
+		writeProblem(w, newProblem(r, http.StatusInternalServerError, errInternal))
+		return
+	}
+
+	w.Header().Set("Content-Type", calendarContentType)
+	w.WriteHeader(http.StatusOK) //200
+
+	now := time.Now()
+	writer := ical.NewWriter(w, h.Location)
+	writer.Begin(feedName(feed), now.AddDate(0, -1, 0), now.AddDate(2, 0, 0))
+	for _, reservation := range reservations {
+		writer.WriteEvent(ical.ReservationEvent(reservation))
+	}
+	writer.End()
+}
+
+func (h *CalendarHandler) serveFeedURL(w http.ResponseWriter, r *http.Request, feed models.CalendarFeed) {
+	if h.FeedSecret == "" {
+		writeProblem(w, newProblem(r, http.StatusForbidden, models.ErrInvalidFeedToken))
+		return
+	}
+
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	feedURL := url.URL{
+		Scheme:   scheme,
+		Host:     r.Host,
+		Path:     fmt.Sprintf("/%ss/%s/calendar.ics", feed.Kind, url.PathEscape(feed.Subject)),
+		RawQuery: url.Values{"token": {feedToken(h.FeedSecret, feed)}}.Encode(),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK) //200
+	json.NewEncoder(w).Encode(map[string]string{"url": feedURL.String()})
+}
+
+// feedToken derives the secret token of a feed, so tokens need no storage
+// and rotating FeedSecret revokes all of them at once.
+func feedToken(secret string, feed models.CalendarFeed) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(feed.Kind + ":" + feed.Subject))
+	return hex.EncodeToString(mac.Sum(nil))[:32]
+}
+
+// validFeedToken reports whether token is the token of the feed. An empty
+// secret disables feed tokens.
+func validFeedToken(secret string, feed models.CalendarFeed, token string) bool {
+	return secret != "" && hmac.Equal([]byte(token), []byte(feedToken(secret, feed)))
+}
+
+func feedName(feed models.CalendarFeed) string {
+	if feed.Kind == models.UserFeed {
+		return "Bookings of " + feed.Subject
+	}
+	return "Room " + feed.Subject
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+)
+
+// Changes returns the reservations written after the since sequence, in
+// write order. Clients keep next_since and pass it back to get the next
+// page or, once has_more is false, later changes. It is mounted behind
+// AdminOnly.
+func (h *ReservationHandler) Changes(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	var since int64
+	if value := query.Get("since"); value != "" {
+		var err error
+		since, err = strconv.ParseInt(value, 10, 64)
+		if err != nil || since < 0 {
+			h.handleError(w, r, models.ErrInvalidParameter.WithField("since"))
+			return
+		}
+	}
+
+	limit := 0
+	if value := query.Get("limit"); value != "" {
+		var err error
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			h.handleError(w, r, models.ErrInvalidParameter.WithField("limit"))
+			return
+		}
+	}
+
+	feed, err := h.ReservationService.GetChanges(r.Context(), since, limit)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK) //200
+	json.NewEncoder(w).Encode(feed)
+}
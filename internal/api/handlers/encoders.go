@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/ical"
+)
+
+// listingEncoder writes a room listing one reservation at a time.
+type listingEncoder interface {
+	Begin(roomID string) error
+	Encode(reservation models.Reservation) error
+	End(nextCursor string) error
+}
+
+// listingFormat is a representation a room listing can be rendered in.
+type listingFormat struct {
+	Name        string
+	MediaType   string
+	ContentType string
+	New         func(w io.Writer, loc *time.Location) listingEncoder
+}
+
+// listingFormats are tried in order when the client accepts several of them
+// with the same quality, so JSON stays the default.
+var listingFormats = []listingFormat{
+	{
+		Name:        "json",
+		MediaType:   "application/json",
+		ContentType: "application/json",
+		New:         func(w io.Writer, _ *time.Location) listingEncoder { return &jsonListingEncoder{w: w} },
+	},
+	{
+		Name:        "csv",
+		MediaType:   "text/csv",
+		ContentType: "text/csv; charset=utf-8",
+		New:         func(w io.Writer, _ *time.Location) listingEncoder { return &csvListingEncoder{w: csv.NewWriter(w)} },
+	},
+	{
+		Name:        "ics",
+		MediaType:   "text/calendar",
+		ContentType: calendarContentType,
+		New: func(w io.Writer, loc *time.Location) listingEncoder {
+			return &icsListingEncoder{w: ical.NewWriter(w, loc)}
+		},
+	},
+}
+
+// negotiateListingFormat picks the representation from the format query
+// parameter or, failing that, the Accept header.
+func negotiateListingFormat(r *http.Request) (*listingFormat, error) {
+	if name := r.URL.Query().Get("format"); name != "" {
+		for i := range listingFormats {
+			if listingFormats[i].Name == name {
+				return &listingFormats[i], nil
+			}
+		}
+		return nil, models.ErrUnsupportedFormat
+	}
+
+	accept := r.Header.Get("Accept")
+	if accept == "" {
+		return &listingFormats[0], nil
+	}
+
+	type acceptRange struct {
+		mediaType string
+		quality   float64
+	}
+	var ranges []acceptRange
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		quality := 1.0
+		if q, err := strconv.ParseFloat(params["q"], 64); err == nil {
+			quality = q
+		}
+		if quality > 0 {
+			ranges = append(ranges, acceptRange{mediaType: mediaType, quality: quality})
+		}
+	}
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].quality > ranges[j].quality })
+
+	for _, ar := range ranges {
+		for i := range listingFormats {
+			format := &listingFormats[i]
+			if ar.mediaType == format.MediaType || ar.mediaType == "*/*" ||
+				ar.mediaType == strings.SplitN(format.MediaType, "/", 2)[0]+"/*" {
+				return format, nil
+			}
+		}
+	}
+
+	return nil, models.ErrNotAcceptable
+}
+
+type jsonListingEncoder struct {
+	w     io.Writer
+	count int
+}
+
+func (e *jsonListingEncoder) Begin(roomID string) error {
+	room, err := json.Marshal(roomID)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(e.w, `{"room_id":`+string(room)+`,"reservations":[`)
+	return err
+}
+
+func (e *jsonListingEncoder) Encode(reservation models.Reservation) error {
+	if e.count > 0 {
+		if _, err := io.WriteString(e.w, ","); err != nil {
+			return err
+		}
+	}
+	e.count++
+
+	b, err := json.Marshal(reservation)
+	if err != nil {
+		return err
+	}
+	_, err = e.w.Write(b)
+	return err
+}
+
+func (e *jsonListingEncoder) End(nextCursor string) error {
+	tail := "]"
+	if nextCursor != "" {
+		cursor, err := json.Marshal(nextCursor)
+		if err != nil {
+			return err
+		}
+		tail += `,"next_cursor":` + string(cursor)
+	}
+	_, err := io.WriteString(e.w, tail+"}\n")
+	return err
+}
+
+type csvListingEncoder struct {
+	w *csv.Writer
+}
+
+func (e *csvListingEncoder) Begin(string) error {
+	return e.w.Write([]string{"id", "room_id", "user_id", "start_time", "end_time", "version", "status"})
+}
+
+func (e *csvListingEncoder) Encode(reservation models.Reservation) error {
+	return e.w.Write([]string{
+		strconv.Itoa(reservation.ID),
+		reservation.RoomID,
+		reservation.UserID,
+		reservation.StartTime.UTC().Format(time.RFC3339),
+		reservation.EndTime.UTC().Format(time.RFC3339),
+		strconv.Itoa(reservation.Version),
+		reservation.Status,
+	})
+}
+
+func (e *csvListingEncoder) End(string) error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+type icsListingEncoder struct {
+	w *ical.Writer
+}
+
+func (e *icsListingEncoder) Begin(roomID string) error {
+	now := time.Now()
+	e.w.Begin("Room "+roomID, now.AddDate(0, -1, 0), now.AddDate(2, 0, 0))
+	return nil
+}
+
+func (e *icsListingEncoder) Encode(reservation models.Reservation) error {
+	e.w.WriteEvent(ical.ReservationEvent(reservation))
+	return nil
+}
+
+func (e *icsListingEncoder) End(string) error {
+	return e.w.End()
+}
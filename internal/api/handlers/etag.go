@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+)
+
+// reservationETag renders the reservation version as a strong entity tag.
+func reservationETag(version int) string {
+	return strconv.Quote(strconv.Itoa(version))
+}
+
+// listingETag is a weak entity tag over a listing fingerprint, distinct for
+// each representation of the same listing.
+func listingETag(fingerprint, format string) string {
+	return `W/"` + fingerprint + "-" + format + `"`
+}
+
+// noneMatch reports whether the If-None-Match header does not list etag.
+func noneMatch(r *http.Request, etag string) bool {
+	for _, candidate := range strings.Split(r.Header.Get("If-None-Match"), ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return false
+		}
+	}
+	return true
+}
+
+// ifMatchVersion parses the If-Match header into the version expected by a
+// conditional write. "*" matches any version and is returned as zero.
+func ifMatchVersion(r *http.Request) (int, error) {
+	value := strings.TrimSpace(r.Header.Get("If-Match"))
+	if value == "" {
+		return 0, models.ErrVersionNotProvided
+	}
+	if value == "*" {
+		return 0, nil
+	}
+
+	unquoted, err := strconv.Unquote(value)
+	if err != nil {
+		return 0, models.ErrVersionMismatch
+	}
+	version, err := strconv.Atoi(unquoted)
+	if err != nil || version <= 0 {
+		return 0, models.ErrVersionMismatch
+	}
+
+	return version, nil
+}
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+	"github.com/go-chi/chi/v5"
+)
+
+// keepAliveInterval keeps idle streams from being closed by proxies.
+const keepAliveInterval = 15 * time.Second
+
+type EventsHandler struct {
+	Stream     models.EventStream
+	FeedSecret string
+	AdminToken string
+}
+
+func NewEventsHandler(stream models.EventStream, feedSecret, adminToken string) *EventsHandler {
+	return &EventsHandler{
+		Stream:     stream,
+		FeedSecret: feedSecret,
+		AdminToken: adminToken,
+	}
+}
+
+// RoomEvents streams the reservation events of one room as Server-Sent
+// Events. It takes the token of the room's calendar feed in the token query
+// parameter, since EventSource cannot send headers, or the admin token.
+func (h *EventsHandler) RoomEvents(w http.ResponseWriter, r *http.Request) {
+	roomID := chi.URLParam(r, "room_id")
+	feed := models.CalendarFeed{Kind: models.RoomFeed, Subject: roomID}
+	if !isAdmin(r, h.AdminToken) && !validFeedToken(h.FeedSecret, feed, r.URL.Query().Get("token")) {
+		writeProblem(w, newProblem(r, http.StatusForbidden, models.ErrInvalidFeedToken))
+		return
+	}
+
+	h.serve(w, r, roomID)
+}
+
+// AllEvents streams the reservation events of every room. It is mounted
+// behind AdminOnly.
+func (h *EventsHandler) AllEvents(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, "")
+}
+
+// serve subscribes before replaying the events after Last-Event-ID, so no
+// event falls between the replay and the live stream, and skips live events
+// that were already replayed.
+func (h *EventsHandler) serve(w http.ResponseWriter, r *http.Request, roomID string) {
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("last_event_id")
+	}
+	resume := lastEventID != ""
+	var lastSeq int64
+	if resume {
+		var err error
+		lastSeq, err = strconv.ParseInt(lastEventID, 10, 64)
+		if err != nil || lastSeq < 0 {
+			writeProblem(w, newProblem(r, http.StatusBadRequest, models.ErrInvalidParameter.WithField("Last-Event-ID")))
+			return
+		}
+	}
+
+	events, cancel := h.Stream.Subscribe(roomID)
+	defer cancel()
+
+	rc := http.NewResponseController(w)
+	// the server write timeout is meant for ordinary responses, not streams
+	rc.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK) //200
+	io.WriteString(w, "retry: 3000\n\n")
+
+	replayed := make(map[int64]bool)
+	for resume {
+		page, err := h.Stream.EventsAfter(r.Context(), lastSeq, roomID)
+		if err != nil {
+			return
+		}
+		for _, event := range page {
+			if err := writeEvent(w, event); err != nil {
+				return
+			}
+			replayed[event.Seq] = true
+			lastSeq = event.Seq
+		}
+		resume = len(page) > 0
+	}
+	if rc.Flush() != nil {
+		return
+	}
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			io.WriteString(w, ": keepalive\n\n")
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if replayed[event.Seq] {
+				continue
+			}
+			if err := writeEvent(w, event); err != nil {
+				return
+			}
+		}
+		if rc.Flush() != nil {
+			return
+		}
+	}
+}
+
+func writeEvent(w io.Writer, event models.ReservationEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.Seq, event.Type, data)
+	return err
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/ical"
+)
+
+// FreeBusy returns the merged busy intervals of several rooms at once. The
+// answer is JSON unless the client asks for text/calendar, in which case it
+// is a VCALENDAR with one VFREEBUSY per room.
+func (h *ReservationHandler) FreeBusy(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" && strings.Contains(r.Header.Get("Accept"), "text/calendar") {
+		format = "ics"
+	}
+	if format != "" && format != "json" && format != "ics" {
+		h.handleError(w, r, models.ErrUnsupportedFormat)
+		return
+	}
+
+	var query models.FreeBusyQuery
+	if err := json.NewDecoder(r.Body).Decode(&query); err != nil {
+		writeProblem(w, newProblem(r, http.StatusBadRequest, errInvalidBody))
+		return
+	}
+
+	freeBusy, err := h.ReservationService.GetFreeBusy(r.Context(), query)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	if format != "ics" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK) //200
+		json.NewEncoder(w).Encode(freeBusy)
+		return
+	}
+
+	w.Header().Set("Content-Type", calendarContentType)
+	w.WriteHeader(http.StatusOK) //200
+
+	now := time.Now()
+	writer := ical.NewWriter(w, time.UTC)
+	writer.Begin("", freeBusy.StartTime, freeBusy.EndTime)
+	for _, room := range freeBusy.Rooms {
+		writer.WriteFreeBusy(room.RoomID, now, freeBusy.StartTime, freeBusy.EndTime, busyPeriods(room.Busy))
+	}
+	writer.End()
+}
+
+func busyPeriods(slots []models.TimeSlot) []ical.Period {
+	periods := make([]ical.Period, 0, len(slots))
+	for _, slot := range slots {
+		periods = append(periods, ical.Period{Start: slot.StartTime, End: slot.EndTime})
+	}
+	return periods
+}
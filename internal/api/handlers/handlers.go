@@ -3,7 +3,10 @@ package handlers
 import (
 	"encoding/json"
 	"errors"
+	"log"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
 	"github.com/go-chi/chi/v5"
@@ -11,8 +14,14 @@ import (
 
 type ReservationHandler struct {
 	ReservationService models.ReservationService
+
+	// Location is the time zone of iCalendar representations, UTC when nil.
+	Location *time.Location
 }
 
+// errNotModified stops a listing stream after a 304 response was sent.
+var errNotModified = errors.New("not modified")
+
 func NewReservationHandler(service models.ReservationService) *ReservationHandler {
 	return &ReservationHandler{
 		ReservationService: service,
@@ -22,64 +31,297 @@ func NewReservationHandler(service models.ReservationService) *ReservationHandle
 func (h *ReservationHandler) Reserve(w http.ResponseWriter, r *http.Request) {
 	var reservation models.Reservation
 	if err := json.NewDecoder(r.Body).Decode(&reservation); err != nil {
-		http.Error(w, "invalid body", http.StatusBadRequest)
+		writeProblem(w, newProblem(r, http.StatusBadRequest, errInvalidBody))
 		return
 	}
 
 	err := h.ReservationService.Create(r.Context(), &reservation)
 	if err != nil {
-		h.handleError(w, err)
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", reservationETag(reservation.Version))
+	w.WriteHeader(http.StatusCreated) //201
+	json.NewEncoder(w).Encode(reservation)
+}
+
+// ReserveBatch books several rooms or slots at once: all of them or, when
+// any item fails, none. A rejected batch reports the result of every item.
+func (h *ReservationHandler) ReserveBatch(w http.ResponseWriter, r *http.Request) {
+	var batch struct {
+		Reservations []models.Reservation `json:"reservations"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+		writeProblem(w, newProblem(r, http.StatusBadRequest, errInvalidBody))
+		return
+	}
+
+	if err := h.ReservationService.CreateBatch(r.Context(), batch.Reservations); err != nil {
+		h.handleError(w, r, err)
 		return
 	}
 
+	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated) //201
+	json.NewEncoder(w).Encode(batch)
 }
 
+// GetReservationsByRoom streams a page of the room's reservations as JSON,
+// CSV or iCalendar, chosen by the format query parameter or the Accept header.
 func (h *ReservationHandler) GetReservationsByRoom(w http.ResponseWriter, r *http.Request) {
 	roomID := chi.URLParam(r, "room_id")
 
-	reservations, err := h.ReservationService.GetByRoomID(r.Context(), roomID)
+	filter, err := parseReservationFilter(r)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	format, err := negotiateListingFormat(r)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	encoder := format.New(w, h.Location)
+	var nextCursor string
+	started := false
+
+	begin := func(info models.ListingInfo) error {
+		etag := listingETag(info.Fingerprint, format.Name)
+		w.Header().Set("Vary", "Accept")
+		w.Header().Set("ETag", etag)
+		if info.NextCursor != "" {
+			w.Header().Set("Link", nextPageLink(r, info.NextCursor))
+		}
+		if !noneMatch(r, etag) {
+			w.WriteHeader(http.StatusNotModified) //304
+			return errNotModified
+		}
+
+		w.Header().Set("Content-Type", format.ContentType)
+		w.WriteHeader(http.StatusOK) //200
+		started = true
+		nextCursor = info.NextCursor
+		return encoder.Begin(roomID)
+	}
+
+	err = h.ReservationService.StreamByRoomID(r.Context(), roomID, filter, begin, encoder.Encode)
+	if errors.Is(err, errNotModified) {
+		return
+	}
+	if err != nil {
+		if started {
+			log.Printf("Listing of room %s aborted: %v", roomID, err)
+			return
+		}
+		h.handleError(w, r, err)
+		return
+	}
+
+	if err := encoder.End(nextCursor); err != nil {
+		log.Printf("Listing of room %s aborted: %v", roomID, err)
+	}
+}
+
+// nextPageLink renders an RFC 8288 Link header pointing to the next page.
+func nextPageLink(r *http.Request, cursor string) string {
+	next := *r.URL
+	query := next.Query()
+	query.Set("cursor", cursor)
+	next.RawQuery = query.Encode()
+	return "<" + next.RequestURI() + `>; rel="next"`
+}
+
+func (h *ReservationHandler) GetReservation(w http.ResponseWriter, r *http.Request) {
+	reservation, err := h.reservationFromPath(r)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", reservationETag(reservation.Version))
+	w.WriteHeader(http.StatusOK) //200
+	json.NewEncoder(w).Encode(reservation)
+}
+
+func (h *ReservationHandler) UpdateReservation(w http.ResponseWriter, r *http.Request) {
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.handleError(w, r, models.ErrNoMatchingReservation)
+		return
+	}
+
+	var reservation models.Reservation
+	if err := json.NewDecoder(r.Body).Decode(&reservation); err != nil {
+		writeProblem(w, newProblem(r, http.StatusBadRequest, errInvalidBody))
+		return
+	}
+	reservation.ID = id
+	reservation.RoomID = chi.URLParam(r, "room_id")
+	reservation.Version = version
+
+	err = h.ReservationService.Update(r.Context(), &reservation)
 	if err != nil {
-		h.handleError(w, err)
+		h.handleError(w, r, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", reservationETag(reservation.Version))
 	w.WriteHeader(http.StatusOK) //200
-	if err := json.NewEncoder(w).Encode(reservations); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	json.NewEncoder(w).Encode(reservation)
+}
+
+func (h *ReservationHandler) DeleteReservation(w http.ResponseWriter, r *http.Request) {
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.handleError(w, r, models.ErrNoMatchingReservation)
+		return
+	}
+
+	reservation := &models.Reservation{
+		ID:      id,
+		RoomID:  chi.URLParam(r, "room_id"),
+		Version: version,
+	}
+	err = h.ReservationService.DeleteByID(r.Context(), reservation)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent) //204
+}
+
+// parseReservationFilter reads the from, to, limit and cursor query
+// parameters of a room listing.
+func parseReservationFilter(r *http.Request) (models.ReservationFilter, error) {
+	var filter models.ReservationFilter
+	query := r.URL.Query()
+
+	for name, dst := range map[string]*time.Time{"from": &filter.From, "to": &filter.To} {
+		if value := query.Get(name); value != "" {
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return filter, models.ErrInvalidParameter.WithField(name)
+			}
+			*dst = t
+		}
+	}
+
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			return filter, models.ErrInvalidParameter.WithField("limit")
+		}
+		filter.Limit = limit
+	}
+
+	if value := query.Get("cursor"); value != "" {
+		cursor, err := models.ParseCursor(value)
+		if err != nil {
+			return filter, err
+		}
+		filter.After = cursor
+	}
+
+	return filter, nil
+}
+
+// reservationFromPath loads the reservation addressed by the room_id and id
+// URL parameters.
+func (h *ReservationHandler) reservationFromPath(r *http.Request) (*models.Reservation, error) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		return nil, models.ErrNoMatchingReservation
+	}
+
+	reservation, err := h.ReservationService.GetByID(r.Context(), id)
+	if err != nil {
+		return nil, err
+	}
+	if reservation.RoomID != chi.URLParam(r, "room_id") {
+		return nil, models.ErrNoMatchingReservation
 	}
+
+	return reservation, nil
 }
 
+// CancelReserve cancels the reservation of a room at the given interval.
+// Like cancelling by id it requires If-Match; "If-Match: *" maps to version
+// 0 and cancels whatever version is current.
 func (h *ReservationHandler) CancelReserve(w http.ResponseWriter, r *http.Request) {
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
 	var reservation models.Reservation
 	if err := json.NewDecoder(r.Body).Decode(&reservation); err != nil {
-		http.Error(w, "invalid body", http.StatusBadRequest)
+		writeProblem(w, newProblem(r, http.StatusBadRequest, errInvalidBody))
 		return
 	}
+	reservation.Version = version
 
-	err := h.ReservationService.DeleteReservation(r.Context(), &reservation)
+	err = h.ReservationService.DeleteReservation(r.Context(), &reservation)
 	if err != nil {
-		h.handleError(w, err)
+		h.handleError(w, r, err)
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent) //204
 }
 
-func (h *ReservationHandler) handleError(w http.ResponseWriter, err error) {
+func (h *ReservationHandler) handleError(w http.ResponseWriter, r *http.Request, err error) {
 	switch {
-	case errors.Is(err, models.ErrRoomAlreadyReservated):
-		http.Error(w, err.Error(), http.StatusConflict)
-	case errors.Is(err, models.ErrNoMatchingReservation):
-		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, models.ErrRoomAlreadyReservated),
+		errors.Is(err, models.ErrSlotAvailable),
+		errors.Is(err, models.ErrNoWaitlistOffer),
+		errors.Is(err, models.ErrHoldNotActive),
+		errors.Is(err, models.ErrReservationNotPending),
+		errors.Is(err, models.ErrCheckInNotOpen),
+		errors.Is(err, models.ErrReservationNotInProgress),
+		errors.Is(err, models.ErrAmbiguousReservation),
+		errors.Is(err, models.ErrBatchConflict):
+		writeProblem(w, newProblem(r, http.StatusConflict, err))
+	case errors.Is(err, models.ErrNoMatchingReservation),
+		errors.Is(err, models.ErrWaitlistEntryNotFound),
+		errors.Is(err, models.ErrHoldNotFound),
+		errors.Is(err, models.ErrResourceNotFound):
+		writeProblem(w, newProblem(r, http.StatusNotFound, err))
+	case errors.Is(err, models.ErrNotAcceptable):
+		writeProblem(w, newProblem(r, http.StatusNotAcceptable, err))
+	case errors.Is(err, models.ErrVersionMismatch):
+		writeProblem(w, newProblem(r, http.StatusPreconditionFailed, err))
+	case errors.Is(err, models.ErrVersionNotProvided):
+		writeProblem(w, newProblem(r, http.StatusPreconditionRequired, err))
 	case errors.Is(err, models.ErrTimeNotProvided),
 		errors.Is(err, models.ErrPastTime),
-		errors.Is(err, models.ErrEndTimeBeforeStartTime), 
-		errors.Is(err, models.ErrReservationTimeExceedingLimit):
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		errors.Is(err, models.ErrEndTimeBeforeStartTime),
+		errors.Is(err, models.ErrReservationTimeExceedingLimit),
+		errors.Is(err, models.ErrInvalidParameter),
+		errors.Is(err, models.ErrInvalidImportRow),
+		errors.Is(err, models.ErrUnsupportedFormat),
+		errors.Is(err, models.ErrBatchInvalid):
+		writeProblem(w, newProblem(r, http.StatusBadRequest, err))
 	default:
-		http.Error(w, "internal server error", http.StatusInternalServerError)
+		writeProblem(w, newProblem(r, http.StatusInternalServerError, errInternal))
 	}
 }
-
@@ -0,0 +1,1092 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/api/handlers"
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models/mocks"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const reservationBody = `{"room_id":"411","start_time":"2030-09-01T10:00:00Z","end_time":"2030-09-01T11:00:00Z"}`
+
+func TestReserveProblemResponses(t *testing.T) {
+	t.Run("invalid body", func(t *testing.T) {
+		service := mocks.NewReservationService(t)
+		handler := handlers.NewReservationHandler(service)
+
+		w := httptest.NewRecorder()
+		handler.Reserve(w, httptest.NewRequest(http.MethodPost, "/reservations", strings.NewReader("{")))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+
+		var problem handlers.Problem
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&problem))
+		assert.Equal(t, "INVALID_BODY", problem.Code)
+	})
+
+	t.Run("past time", func(t *testing.T) {
+		service := mocks.NewReservationService(t)
+		service.On("Create", mock.Anything, mock.Anything).Return(models.ErrPastTime)
+		handler := handlers.NewReservationHandler(service)
+
+		w := httptest.NewRecorder()
+		handler.Reserve(w, httptest.NewRequest(http.MethodPost, "/reservations", strings.NewReader(reservationBody)))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var problem handlers.Problem
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&problem))
+		assert.Equal(t, "PAST_TIME", problem.Code)
+		assert.Equal(t, "start_time", problem.Field)
+		assert.Equal(t, http.StatusBadRequest, problem.Status)
+	})
+
+	t.Run("room conflict", func(t *testing.T) {
+		existing := models.Reservation{
+			ID:        7,
+			RoomID:    "411",
+			StartTime: time.Date(2030, 9, 1, 10, 30, 0, 0, time.UTC),
+			EndTime:   time.Date(2030, 9, 1, 11, 30, 0, 0, time.UTC),
+		}
+		service := mocks.NewReservationService(t)
+		service.On("Create", mock.Anything, mock.Anything).
+			Return(&models.ConflictError{Conflicts: []models.Reservation{existing}})
+		handler := handlers.NewReservationHandler(service)
+
+		w := httptest.NewRecorder()
+		handler.Reserve(w, httptest.NewRequest(http.MethodPost, "/reservations", strings.NewReader(reservationBody)))
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+
+		var problem handlers.Problem
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&problem))
+		assert.Equal(t, "ROOM_CONFLICT", problem.Code)
+		require.Len(t, problem.Conflicts, 1)
+		assert.Equal(t, existing.ID, problem.Conflicts[0].ID)
+	})
+}
+
+func TestIdempotency(t *testing.T) {
+	created := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	t.Run("first request is stored", func(t *testing.T) {
+		storage := mocks.NewIdempotencyStorage(t)
+		storage.On("Claim", mock.Anything, mock.Anything).Return(true, nil)
+		storage.On("Complete", mock.Anything, mock.MatchedBy(func(record *models.IdempotencyRecord) bool {
+			return record.Key == "abc" && record.StatusCode == http.StatusCreated
+		})).Return(nil)
+
+		req := httptest.NewRequest(http.MethodPost, "/reservations/", strings.NewReader(reservationBody))
+		req.Header.Set("Idempotency-Key", "abc")
+		w := httptest.NewRecorder()
+		handlers.Idempotency(storage, time.Hour)(created).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+
+	t.Run("retry replays stored response", func(t *testing.T) {
+		var hash string
+		storage := mocks.NewIdempotencyStorage(t)
+		storage.On("Claim", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			hash = args.Get(1).(*models.IdempotencyRecord).RequestHash
+		}).Return(false, nil)
+		storage.On("Get", mock.Anything, "abc", http.MethodPost, "/reservations/").Return(func(_ context.Context, key, method, path string) *models.IdempotencyRecord {
+			return &models.IdempotencyRecord{Key: key, Method: method, Path: path, RequestHash: hash, StatusCode: http.StatusCreated}
+		}, nil)
+
+		req := httptest.NewRequest(http.MethodPost, "/reservations/", strings.NewReader(reservationBody))
+		req.Header.Set("Idempotency-Key", "abc")
+		w := httptest.NewRecorder()
+		handlers.Idempotency(storage, time.Hour)(http.NotFoundHandler()).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, "true", w.Header().Get("Idempotent-Replayed"))
+	})
+
+	t.Run("key reused with different body", func(t *testing.T) {
+		storage := mocks.NewIdempotencyStorage(t)
+		storage.On("Claim", mock.Anything, mock.Anything).Return(false, nil)
+		storage.On("Get", mock.Anything, "abc", http.MethodPost, "/reservations/").
+			Return(&models.IdempotencyRecord{RequestHash: "other", StatusCode: http.StatusCreated}, nil)
+
+		req := httptest.NewRequest(http.MethodPost, "/reservations/", strings.NewReader(reservationBody))
+		req.Header.Set("Idempotency-Key", "abc")
+		w := httptest.NewRecorder()
+		handlers.Idempotency(storage, time.Hour)(created).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+		var problem handlers.Problem
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&problem))
+		assert.Equal(t, "IDEMPOTENCY_KEY_REUSED", problem.Code)
+	})
+
+	t.Run("response stored after client disconnects", func(t *testing.T) {
+		storage := mocks.NewIdempotencyStorage(t)
+		storage.On("Claim", mock.Anything, mock.Anything).Return(true, nil)
+		storage.On("Complete", mock.MatchedBy(func(ctx context.Context) bool {
+			return ctx.Err() == nil
+		}), mock.Anything).Return(nil)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		disconnecting := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			cancel()
+			w.WriteHeader(http.StatusCreated)
+		})
+
+		req := httptest.NewRequest(http.MethodPost, "/reservations/", strings.NewReader(reservationBody)).WithContext(ctx)
+		req.Header.Set("Idempotency-Key", "abc")
+		w := httptest.NewRecorder()
+		handlers.Idempotency(storage, time.Hour)(disconnecting).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+
+	t.Run("body too large", func(t *testing.T) {
+		storage := mocks.NewIdempotencyStorage(t)
+
+		req := httptest.NewRequest(http.MethodPost, "/reservations/", strings.NewReader(strings.Repeat("x", 1<<20+1)))
+		req.Header.Set("Idempotency-Key", "abc")
+		w := httptest.NewRecorder()
+		handlers.Idempotency(storage, time.Hour)(created).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	})
+}
+
+func TestUpdateReservationPreconditions(t *testing.T) {
+	newRequest := func(ifMatch string) *http.Request {
+		req := httptest.NewRequest(http.MethodPut, "/reservations/411/7", strings.NewReader(reservationBody))
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("room_id", "411")
+		rctx.URLParams.Add("id", "7")
+		return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	}
+
+	t.Run("missing If-Match", func(t *testing.T) {
+		handler := handlers.NewReservationHandler(mocks.NewReservationService(t))
+
+		w := httptest.NewRecorder()
+		handler.UpdateReservation(w, newRequest(""))
+
+		assert.Equal(t, http.StatusPreconditionRequired, w.Code)
+	})
+
+	t.Run("stale version", func(t *testing.T) {
+		service := mocks.NewReservationService(t)
+		service.On("Update", mock.Anything, mock.MatchedBy(func(reservation *models.Reservation) bool {
+			return reservation.ID == 7 && reservation.RoomID == "411" && reservation.Version == 2
+		})).Return(models.ErrVersionMismatch)
+		handler := handlers.NewReservationHandler(service)
+
+		w := httptest.NewRecorder()
+		handler.UpdateReservation(w, newRequest(`"2"`))
+
+		assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+	})
+
+	t.Run("current version", func(t *testing.T) {
+		service := mocks.NewReservationService(t)
+		service.On("Update", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			args.Get(1).(*models.Reservation).Version = 3
+		}).Return(nil)
+		handler := handlers.NewReservationHandler(service)
+
+		w := httptest.NewRecorder()
+		handler.UpdateReservation(w, newRequest(`"2"`))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `"3"`, w.Header().Get("ETag"))
+	})
+}
+
+func TestCancelReservePreconditions(t *testing.T) {
+	newRequest := func(ifMatch string) *http.Request {
+		req := httptest.NewRequest(http.MethodDelete, "/reservations", strings.NewReader(reservationBody))
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		return req
+	}
+
+	t.Run("missing If-Match", func(t *testing.T) {
+		handler := handlers.NewReservationHandler(mocks.NewReservationService(t))
+
+		w := httptest.NewRecorder()
+		handler.CancelReserve(w, newRequest(""))
+
+		assert.Equal(t, http.StatusPreconditionRequired, w.Code)
+	})
+
+	t.Run("stale version", func(t *testing.T) {
+		service := mocks.NewReservationService(t)
+		service.On("DeleteReservation", mock.Anything, mock.MatchedBy(func(reservation *models.Reservation) bool {
+			return reservation.RoomID == "411" && reservation.Version == 2
+		})).Return(models.ErrVersionMismatch)
+		handler := handlers.NewReservationHandler(service)
+
+		w := httptest.NewRecorder()
+		handler.CancelReserve(w, newRequest(`"2"`))
+
+		assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+	})
+
+	t.Run("wildcard cancels any version", func(t *testing.T) {
+		service := mocks.NewReservationService(t)
+		service.On("DeleteReservation", mock.Anything, mock.MatchedBy(func(reservation *models.Reservation) bool {
+			return reservation.Version == 0
+		})).Return(nil)
+		handler := handlers.NewReservationHandler(service)
+
+		w := httptest.NewRecorder()
+		handler.CancelReserve(w, newRequest("*"))
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+	})
+}
+
+func TestGetReservationsByRoomNegotiation(t *testing.T) {
+	reservation := models.Reservation{
+		ID:        1,
+		RoomID:    "411",
+		StartTime: time.Date(2030, 9, 1, 10, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2030, 9, 1, 11, 0, 0, 0, time.UTC),
+		Status:    models.StatusApproved,
+		Version:   1,
+	}
+	newService := func(t *testing.T) *mocks.ReservationService {
+		service := mocks.NewReservationService(t)
+		service.On("StreamByRoomID", mock.Anything, "411", mock.Anything, mock.Anything, mock.Anything).
+			Return(func(_ context.Context, _ string, _ models.ReservationFilter, begin func(models.ListingInfo) error, fn func(models.Reservation) error) error {
+				if err := begin(models.ListingInfo{Fingerprint: "abc", NextCursor: "next"}); err != nil {
+					return err
+				}
+				return fn(reservation)
+			})
+		return service
+	}
+	newRequest := func(target, accept string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("room_id", "411")
+		return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	}
+
+	t.Run("json by default", func(t *testing.T) {
+		handler := handlers.NewReservationHandler(newService(t))
+
+		w := httptest.NewRecorder()
+		handler.GetReservationsByRoom(w, newRequest("/reservations/411", ""))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+		assert.Equal(t, `W/"abc-json"`, w.Header().Get("ETag"))
+
+		var listing models.RoomReservations
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&listing))
+		assert.Equal(t, "411", listing.RoomID)
+		assert.Len(t, listing.Reservations, 1)
+		assert.Equal(t, "next", listing.NextCursor)
+	})
+
+	t.Run("csv by accept header", func(t *testing.T) {
+		handler := handlers.NewReservationHandler(newService(t))
+
+		w := httptest.NewRecorder()
+		handler.GetReservationsByRoom(w, newRequest("/reservations/411", "text/csv, application/json;q=0.5"))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Equal(t, "id,room_id,user_id,start_time,end_time,version,status\n1,411,,2030-09-01T10:00:00Z,2030-09-01T11:00:00Z,1,approved\n", w.Body.String())
+	})
+
+	t.Run("ics by format parameter", func(t *testing.T) {
+		handler := handlers.NewReservationHandler(newService(t))
+
+		w := httptest.NewRecorder()
+		handler.GetReservationsByRoom(w, newRequest("/reservations/411?format=ics", "application/json"))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "UID:reservation-1@meeting-room-booking\r\n")
+	})
+
+	t.Run("not modified", func(t *testing.T) {
+		handler := handlers.NewReservationHandler(newService(t))
+
+		req := newRequest("/reservations/411", "")
+		req.Header.Set("If-None-Match", `W/"abc-json"`)
+		w := httptest.NewRecorder()
+		handler.GetReservationsByRoom(w, req)
+
+		assert.Equal(t, http.StatusNotModified, w.Code)
+		assert.Empty(t, w.Body.String())
+	})
+
+	t.Run("not acceptable", func(t *testing.T) {
+		handler := handlers.NewReservationHandler(mocks.NewReservationService(t))
+
+		w := httptest.NewRecorder()
+		handler.GetReservationsByRoom(w, newRequest("/reservations/411", "application/xml"))
+
+		assert.Equal(t, http.StatusNotAcceptable, w.Code)
+	})
+}
+
+func TestCalDAV(t *testing.T) {
+	const event = "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nBEGIN:VEVENT\r\nUID:abc-123\r\n" +
+		"DTSTART:20300901T100000Z\r\nDTEND:20300901T110000Z\r\nORGANIZER:mailto:alice@example.com\r\n" +
+		"END:VEVENT\r\nEND:VCALENDAR\r\n"
+	newRequest := func(method, target, body string) *http.Request {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("room_id", "411")
+		return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	}
+
+	t.Run("put creates a reservation", func(t *testing.T) {
+		service := mocks.NewReservationService(t)
+		service.On("GetByICalUID", mock.Anything, "411", "abc-123").Return(nil, models.ErrNoMatchingReservation)
+		service.On("Create", mock.Anything, mock.MatchedBy(func(reservation *models.Reservation) bool {
+			return reservation.RoomID == "411" && reservation.ICalUID == "abc-123" &&
+				reservation.UserID == "alice@example.com" &&
+				reservation.StartTime.Equal(time.Date(2030, 9, 1, 10, 0, 0, 0, time.UTC))
+		})).Run(func(args mock.Arguments) {
+			args.Get(1).(*models.Reservation).Version = 1
+		}).Return(nil)
+		handler := handlers.NewCalDAVHandler(service, time.UTC)
+
+		w := httptest.NewRecorder()
+		handler.PutObject(w, newRequest(http.MethodPut, "/caldav/rooms/411/abc-123.ics", event))
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, `"1"`, w.Header().Get("ETag"))
+	})
+
+	t.Run("put reports conflicts", func(t *testing.T) {
+		service := mocks.NewReservationService(t)
+		service.On("GetByICalUID", mock.Anything, "411", "abc-123").Return(nil, models.ErrNoMatchingReservation)
+		service.On("Create", mock.Anything, mock.Anything).Return(&models.ConflictError{
+			Conflicts: []models.Reservation{{ID: 5, RoomID: "411"}},
+		})
+		handler := handlers.NewCalDAVHandler(service, time.UTC)
+
+		w := httptest.NewRecorder()
+		handler.PutObject(w, newRequest(http.MethodPut, "/caldav/rooms/411/abc-123.ics", event))
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), `<E:no-reservation-conflict xmlns:E="urn:meeting-room-booking">`)
+		assert.Contains(t, w.Body.String(), "<D:href>/caldav/rooms/411/reservation-5@meeting-room-booking.ics</D:href>")
+	})
+
+	t.Run("put requires the resource to be named after the uid", func(t *testing.T) {
+		handler := handlers.NewCalDAVHandler(mocks.NewReservationService(t), time.UTC)
+
+		w := httptest.NewRecorder()
+		handler.PutObject(w, newRequest(http.MethodPut, "/caldav/rooms/411/other.ics", event))
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, w.Body.String(), "valid-calendar-object-resource")
+	})
+
+	t.Run("calendar query", func(t *testing.T) {
+		service := mocks.NewReservationService(t)
+		service.On("GetByRoomID", mock.Anything, "411", mock.MatchedBy(func(filter models.ReservationFilter) bool {
+			return filter.From.Equal(time.Date(2030, 9, 1, 0, 0, 0, 0, time.UTC)) &&
+				filter.To.Equal(time.Date(2030, 9, 2, 0, 0, 0, 0, time.UTC))
+		})).Return(&models.RoomReservations{
+			RoomID: "411",
+			Reservations: []models.Reservation{{
+				ID:        1,
+				RoomID:    "411",
+				StartTime: time.Date(2030, 9, 1, 10, 0, 0, 0, time.UTC),
+				EndTime:   time.Date(2030, 9, 1, 11, 0, 0, 0, time.UTC),
+				Version:   2,
+			}},
+		}, nil)
+		handler := handlers.NewCalDAVHandler(service, time.UTC)
+
+		body := `<?xml version="1.0"?>
+<C:calendar-query xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav">
+  <D:prop><D:getetag/><C:calendar-data/></D:prop>
+  <C:filter><C:comp-filter name="VCALENDAR"><C:comp-filter name="VEVENT">
+    <C:time-range start="20300901T000000Z" end="20300902T000000Z"/>
+  </C:comp-filter></C:comp-filter></C:filter>
+</C:calendar-query>`
+		w := httptest.NewRecorder()
+		handler.Report(w, newRequest("REPORT", "/caldav/rooms/411/", body))
+
+		assert.Equal(t, http.StatusMultiStatus, w.Code)
+		assert.Contains(t, w.Body.String(), "<D:href>/caldav/rooms/411/reservation-1@meeting-room-booking.ics</D:href>")
+		assert.Contains(t, w.Body.String(), "<D:getetag>&#34;2&#34;</D:getetag>")
+		assert.Contains(t, w.Body.String(), "UID:reservation-1@meeting-room-booking")
+		assert.NotContains(t, w.Body.String(), "METHOD:PUBLISH")
+	})
+
+	t.Run("free busy query", func(t *testing.T) {
+		service := mocks.NewReservationService(t)
+		service.On("GetFreeBusy", mock.Anything, mock.MatchedBy(func(query models.FreeBusyQuery) bool {
+			return len(query.RoomIDs) == 1 && query.RoomIDs[0] == "411"
+		})).Return(&models.FreeBusy{
+			Rooms: []models.RoomBusy{{
+				RoomID: "411",
+				Busy: []models.TimeSlot{{
+					StartTime: time.Date(2030, 9, 1, 10, 0, 0, 0, time.UTC),
+					EndTime:   time.Date(2030, 9, 1, 11, 0, 0, 0, time.UTC),
+				}},
+			}},
+		}, nil)
+		handler := handlers.NewCalDAVHandler(service, time.UTC)
+
+		body := `<C:free-busy-query xmlns:C="urn:ietf:params:xml:ns:caldav">
+  <C:time-range start="20300901T000000Z" end="20300902T000000Z"/>
+</C:free-busy-query>`
+		w := httptest.NewRecorder()
+		handler.Report(w, newRequest("REPORT", "/caldav/rooms/411/", body))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "FREEBUSY;FBTYPE=BUSY:20300901T100000Z/20300901T110000Z\r\n")
+	})
+
+	t.Run("delete unknown object", func(t *testing.T) {
+		service := mocks.NewReservationService(t)
+		service.On("GetByID", mock.Anything, 9).Return(nil, models.ErrNoMatchingReservation)
+		handler := handlers.NewCalDAVHandler(service, time.UTC)
+
+		w := httptest.NewRecorder()
+		handler.DeleteObject(w, newRequest(http.MethodDelete, "/caldav/rooms/411/reservation-9@meeting-room-booking.ics", ""))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestFreeBusy(t *testing.T) {
+	const body = `{"room_ids":["411","412"],"start_time":"2030-09-01T00:00:00Z","end_time":"2030-09-02T00:00:00Z"}`
+	freeBusy := &models.FreeBusy{
+		StartTime: time.Date(2030, 9, 1, 0, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2030, 9, 2, 0, 0, 0, 0, time.UTC),
+		Rooms: []models.RoomBusy{
+			{RoomID: "411", Busy: []models.TimeSlot{{
+				StartTime: time.Date(2030, 9, 1, 10, 0, 0, 0, time.UTC),
+				EndTime:   time.Date(2030, 9, 1, 12, 0, 0, 0, time.UTC),
+			}}},
+			{RoomID: "412", Busy: []models.TimeSlot{}},
+		},
+	}
+	newService := func(t *testing.T) *mocks.ReservationService {
+		service := mocks.NewReservationService(t)
+		service.On("GetFreeBusy", mock.Anything, mock.MatchedBy(func(query models.FreeBusyQuery) bool {
+			return len(query.RoomIDs) == 2 && query.StartTime.Equal(freeBusy.StartTime)
+		})).Return(freeBusy, nil)
+		return service
+	}
+
+	t.Run("json", func(t *testing.T) {
+		handler := handlers.NewReservationHandler(newService(t))
+
+		w := httptest.NewRecorder()
+		handler.FreeBusy(w, httptest.NewRequest(http.MethodPost, "/freebusy", strings.NewReader(body)))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var got models.FreeBusy
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&got))
+		require.Len(t, got.Rooms, 2)
+		assert.Len(t, got.Rooms[0].Busy, 1)
+		assert.Empty(t, got.Rooms[1].Busy)
+	})
+
+	t.Run("vfreebusy", func(t *testing.T) {
+		handler := handlers.NewReservationHandler(newService(t))
+
+		req := httptest.NewRequest(http.MethodPost, "/freebusy", strings.NewReader(body))
+		req.Header.Set("Accept", "text/calendar")
+		w := httptest.NewRecorder()
+		handler.FreeBusy(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, 2, strings.Count(w.Body.String(), "BEGIN:VFREEBUSY\r\n"))
+		assert.Contains(t, w.Body.String(), "X-ROOM-ID:411\r\nFREEBUSY;FBTYPE=BUSY:20300901T100000Z/20300901T120000Z\r\n")
+	})
+
+	t.Run("invalid window", func(t *testing.T) {
+		service := mocks.NewReservationService(t)
+		service.On("GetFreeBusy", mock.Anything, mock.Anything).Return(nil, models.ErrEndTimeBeforeStartTime)
+		handler := handlers.NewReservationHandler(service)
+
+		w := httptest.NewRecorder()
+		handler.FreeBusy(w, httptest.NewRequest(http.MethodPost, "/freebusy", strings.NewReader(body)))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestWebhookHandler(t *testing.T) {
+	t.Run("create returns the secret", func(t *testing.T) {
+		service := mocks.NewWebhookService(t)
+		service.On("CreateWebhook", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			webhook := args.Get(1).(*models.Webhook)
+			webhook.ID = 1
+			webhook.Secret = "generated"
+		}).Return(nil)
+		handler := handlers.NewWebhookHandler(service)
+
+		w := httptest.NewRecorder()
+		handler.CreateWebhook(w, httptest.NewRequest(http.MethodPost, "/admin/webhooks", strings.NewReader(`{"url":"https://example.com/hook"}`)))
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var webhook models.Webhook
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&webhook))
+		assert.Equal(t, "generated", webhook.Secret)
+	})
+
+	t.Run("retry unknown delivery", func(t *testing.T) {
+		service := mocks.NewWebhookService(t)
+		service.On("RetryDelivery", mock.Anything, 1, int64(9)).Return(models.ErrWebhookNotFound)
+		handler := handlers.NewWebhookHandler(service)
+
+		req := httptest.NewRequest(http.MethodPost, "/admin/webhooks/1/deliveries/9/retry", nil)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", "1")
+		rctx.URLParams.Add("delivery_id", "9")
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+		w := httptest.NewRecorder()
+		handler.RetryDelivery(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestEventsHandler(t *testing.T) {
+	event := func(id int64, eventType string) models.ReservationEvent {
+		return models.ReservationEvent{ID: id, Seq: id, Type: eventType, Reservation: models.Reservation{ID: int(id), RoomID: "411"}}
+	}
+
+	t.Run("replays after Last-Event-ID and skips duplicates", func(t *testing.T) {
+		live := make(chan models.ReservationEvent, 2)
+		live <- event(5, models.EventReservationUpdated)
+		live <- event(6, models.EventReservationCancelled)
+		close(live)
+
+		stream := mocks.NewEventStream(t)
+		stream.On("Subscribe", "411").Return((<-chan models.ReservationEvent)(live), func() {})
+		stream.On("EventsAfter", mock.Anything, int64(3), "411").
+			Return([]models.ReservationEvent{event(4, models.EventReservationCreated), event(5, models.EventReservationUpdated)}, nil)
+		stream.On("EventsAfter", mock.Anything, int64(5), "411").Return([]models.ReservationEvent{}, nil)
+		handler := handlers.NewEventsHandler(stream, "", "admin-token")
+
+		req := httptest.NewRequest(http.MethodGet, "/rooms/411/events", nil)
+		req.Header.Set("Authorization", "Bearer admin-token")
+		req.Header.Set("Last-Event-ID", "3")
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("room_id", "411")
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+		w := httptest.NewRecorder()
+		handler.RoomEvents(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+		body := w.Body.String()
+		assert.Equal(t, 1, strings.Count(body, "id: 5\n"))
+		assert.Less(t, strings.Index(body, "id: 4\nevent: reservation.created\n"), strings.Index(body, "id: 5\n"))
+		assert.Contains(t, body, "id: 6\nevent: reservation.cancelled\ndata: {")
+	})
+
+	t.Run("room stream needs the feed token", func(t *testing.T) {
+		handler := handlers.NewEventsHandler(mocks.NewEventStream(t), "secret", "admin-token")
+
+		serve := func(target string) int {
+			req := httptest.NewRequest(http.MethodGet, target, nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("room_id", "411")
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			w := httptest.NewRecorder()
+			handler.RoomEvents(w, req)
+			return w.Code
+		}
+
+		assert.Equal(t, http.StatusForbidden, serve("/rooms/411/events"))
+		assert.Equal(t, http.StatusForbidden, serve("/rooms/411/events?token=wrong"))
+	})
+
+	t.Run("invalid Last-Event-ID", func(t *testing.T) {
+		handler := handlers.NewEventsHandler(mocks.NewEventStream(t), "", "")
+
+		req := httptest.NewRequest(http.MethodGet, "/events", nil)
+		req.Header.Set("Last-Event-ID", "abc")
+
+		w := httptest.NewRecorder()
+		handler.AllEvents(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestChanges(t *testing.T) {
+	t.Run("passes the cursor through", func(t *testing.T) {
+		service := mocks.NewReservationService(t)
+		service.On("GetChanges", mock.Anything, int64(41), 2).Return(&models.ChangeFeed{
+			Changes:   []models.Change{{Seq: 42, Deleted: true, Reservation: models.Reservation{ID: 7, RoomID: "411"}}},
+			NextSince: 42,
+		}, nil)
+		handler := handlers.NewReservationHandler(service)
+
+		w := httptest.NewRecorder()
+		handler.Changes(w, httptest.NewRequest(http.MethodGet, "/changes?since=41&limit=2", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var feed models.ChangeFeed
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&feed))
+		assert.Equal(t, int64(42), feed.NextSince)
+		require.Len(t, feed.Changes, 1)
+		assert.True(t, feed.Changes[0].Deleted)
+	})
+
+	t.Run("invalid since", func(t *testing.T) {
+		handler := handlers.NewReservationHandler(mocks.NewReservationService(t))
+
+		w := httptest.NewRecorder()
+		handler.Changes(w, httptest.NewRequest(http.MethodGet, "/changes?since=-1", nil))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestWaitlist(t *testing.T) {
+	t.Run("free slot", func(t *testing.T) {
+		service := mocks.NewReservationService(t)
+		service.On("JoinWaitlist", mock.Anything, mock.Anything).Return(models.ErrSlotAvailable)
+		handler := handlers.NewReservationHandler(service)
+
+		body := `{"room_id":"411","user_id":"alice","start_time":"2030-09-01T10:00:00Z","end_time":"2030-09-01T11:00:00Z"}`
+		w := httptest.NewRecorder()
+		handler.JoinWaitlist(w, httptest.NewRequest(http.MethodPost, "/waitlist", strings.NewReader(body)))
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), `"code":"SLOT_AVAILABLE"`)
+	})
+
+	t.Run("claim books the offer", func(t *testing.T) {
+		service := mocks.NewReservationService(t)
+		service.On("ClaimWaitlistOffer", mock.Anything, 3).Return(&models.Reservation{ID: 9, RoomID: "411", Version: 1}, nil)
+		handler := handlers.NewReservationHandler(service)
+
+		req := httptest.NewRequest(http.MethodPost, "/waitlist/3/claim", nil)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", "3")
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+		w := httptest.NewRecorder()
+		handler.ClaimWaitlistOffer(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, `"1"`, w.Header().Get("ETag"))
+	})
+}
+
+func TestHolds(t *testing.T) {
+	withID := func(req *http.Request, id string) *http.Request {
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", id)
+		return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	}
+
+	t.Run("create", func(t *testing.T) {
+		service := mocks.NewReservationService(t)
+		service.On("CreateHold", mock.Anything, mock.MatchedBy(func(hold *models.Hold) bool {
+			return hold.RoomID == "411" && hold.TTLSeconds == 120
+		})).Run(func(args mock.Arguments) {
+			hold := args.Get(1).(*models.Hold)
+			hold.ID = 4
+			hold.Status = models.HoldActive
+		}).Return(nil)
+		handler := handlers.NewReservationHandler(service)
+
+		body := `{"room_id":"411","start_time":"2030-09-01T10:00:00Z","end_time":"2030-09-01T11:00:00Z","ttl_seconds":120}`
+		w := httptest.NewRecorder()
+		handler.CreateHold(w, httptest.NewRequest(http.MethodPost, "/holds", strings.NewReader(body)))
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), `"status":"active"`)
+	})
+
+	t.Run("confirm expired hold", func(t *testing.T) {
+		service := mocks.NewReservationService(t)
+		service.On("ConfirmHold", mock.Anything, 4).Return(nil, models.ErrHoldNotActive)
+		handler := handlers.NewReservationHandler(service)
+
+		w := httptest.NewRecorder()
+		handler.ConfirmHold(w, withID(httptest.NewRequest(http.MethodPost, "/holds/4/confirm", nil), "4"))
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), `"code":"HOLD_NOT_ACTIVE"`)
+	})
+
+	t.Run("release unknown hold", func(t *testing.T) {
+		handler := handlers.NewReservationHandler(mocks.NewReservationService(t))
+
+		w := httptest.NewRecorder()
+		handler.ReleaseHold(w, withID(httptest.NewRequest(http.MethodDelete, "/holds/x", nil), "x"))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestApprovals(t *testing.T) {
+	withParam := func(req *http.Request, key, value string) *http.Request {
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add(key, value)
+		return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	}
+
+	t.Run("update room keeps missing settings", func(t *testing.T) {
+		service := mocks.NewReservationService(t)
+		room := models.DefaultRoom("aud-1")
+		service.On("GetRoom", mock.Anything, "aud-1").Return(&room, nil)
+		service.On("UpdateRoom", mock.Anything, mock.MatchedBy(func(room *models.Room) bool {
+			return room.ID == "aud-1" && room.RequiresApproval && room.PendingBlocks
+		})).Return(nil)
+		handler := handlers.NewReservationHandler(service)
+
+		body := `{"room_id":"other","requires_approval":true}`
+		w := httptest.NewRecorder()
+		handler.UpdateRoom(w, withParam(httptest.NewRequest(http.MethodPut, "/admin/rooms/aud-1", strings.NewReader(body)), "room_id", "aud-1"))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"requires_approval":true`)
+	})
+
+	t.Run("nested combined room", func(t *testing.T) {
+		service := mocks.NewReservationService(t)
+		room := models.DefaultRoom("hall-a")
+		service.On("GetRoom", mock.Anything, "hall-a").Return(&room, nil)
+		service.On("UpdateRoom", mock.Anything, mock.MatchedBy(func(room *models.Room) bool {
+			return room.ParentID == "hall-a"
+		})).Return(models.ErrInvalidParameter.WithField("parent_id"))
+		handler := handlers.NewReservationHandler(service)
+
+		body := `{"parent_id":"hall-a"}`
+		w := httptest.NewRecorder()
+		handler.UpdateRoom(w, withParam(httptest.NewRequest(http.MethodPut, "/admin/rooms/hall-a", strings.NewReader(body)), "room_id", "hall-a"))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"field":"parent_id"`)
+	})
+
+	t.Run("approve", func(t *testing.T) {
+		service := mocks.NewReservationService(t)
+		service.On("ApproveReservation", mock.Anything, 7).Return(&models.Reservation{ID: 7, RoomID: "aud-1", Status: models.StatusApproved, Version: 2}, nil)
+		handler := handlers.NewReservationHandler(service)
+
+		w := httptest.NewRecorder()
+		handler.ApproveReservation(w, withParam(httptest.NewRequest(http.MethodPost, "/admin/approvals/7/approve", nil), "id", "7"))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `"2"`, w.Header().Get("ETag"))
+		assert.Contains(t, w.Body.String(), `"status":"approved"`)
+	})
+
+	t.Run("reject decided reservation", func(t *testing.T) {
+		service := mocks.NewReservationService(t)
+		service.On("RejectReservation", mock.Anything, 7).Return(nil, models.ErrReservationNotPending)
+		handler := handlers.NewReservationHandler(service)
+
+		w := httptest.NewRecorder()
+		handler.RejectReservation(w, withParam(httptest.NewRequest(http.MethodPost, "/admin/approvals/7/reject", nil), "id", "7"))
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), `"code":"RESERVATION_NOT_PENDING"`)
+	})
+}
+
+func TestMeetingOperations(t *testing.T) {
+	withID := func(req *http.Request, id string) *http.Request {
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", id)
+		return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	}
+
+	t.Run("checked in", func(t *testing.T) {
+		checkedIn := time.Date(2030, 9, 1, 10, 3, 0, 0, time.UTC)
+		service := mocks.NewReservationService(t)
+		service.On("CheckIn", mock.Anything, 7).Return(&models.Reservation{ID: 7, RoomID: "411", Status: models.StatusApproved, CheckedInAt: &checkedIn, Version: 2}, nil)
+		handler := handlers.NewReservationHandler(service)
+
+		w := httptest.NewRecorder()
+		handler.CheckIn(w, withID(httptest.NewRequest(http.MethodPost, "/reservations/7/checkin", nil), "7"))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"checked_in_at":"2030-09-01T10:03:00Z"`)
+	})
+
+	t.Run("too early", func(t *testing.T) {
+		service := mocks.NewReservationService(t)
+		service.On("CheckIn", mock.Anything, 7).Return(nil, models.ErrCheckInNotOpen)
+		handler := handlers.NewReservationHandler(service)
+
+		w := httptest.NewRecorder()
+		handler.CheckIn(w, withID(httptest.NewRequest(http.MethodPost, "/reservations/7/checkin", nil), "7"))
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), `"code":"CHECKIN_NOT_OPEN"`)
+	})
+
+	t.Run("extend by minutes", func(t *testing.T) {
+		service := mocks.NewReservationService(t)
+		service.On("ExtendReservation", mock.Anything, 7, 30*time.Minute).Return(&models.Reservation{ID: 7, RoomID: "411", Version: 3}, nil)
+		handler := handlers.NewReservationHandler(service)
+
+		w := httptest.NewRecorder()
+		handler.ExtendReservation(w, withID(httptest.NewRequest(http.MethodPost, "/reservations/7/extend?minutes=30", nil), "7"))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `"3"`, w.Header().Get("ETag"))
+	})
+
+	t.Run("extend by invalid minutes", func(t *testing.T) {
+		handler := handlers.NewReservationHandler(mocks.NewReservationService(t))
+
+		w := httptest.NewRecorder()
+		handler.ExtendReservation(w, withID(httptest.NewRequest(http.MethodPost, "/reservations/7/extend?minutes=-5", nil), "7"))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"field":"minutes"`)
+	})
+
+	t.Run("end a meeting that is over", func(t *testing.T) {
+		service := mocks.NewReservationService(t)
+		service.On("EndReservation", mock.Anything, 7).Return(nil, models.ErrReservationNotInProgress)
+		handler := handlers.NewReservationHandler(service)
+
+		w := httptest.NewRecorder()
+		handler.EndReservation(w, withID(httptest.NewRequest(http.MethodPost, "/reservations/7/end", nil), "7"))
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
+
+func TestKiosk(t *testing.T) {
+	newRouter := func(service models.ReservationService, secret string) *chi.Mux {
+		handler := handlers.NewReservationHandler(service)
+		device := handlers.NewDeviceHandler(secret)
+
+		r := chi.NewRouter()
+		r.Get("/rooms/{room_id}/status", handler.RoomStatus)
+		r.With(device.Authorize).Post("/rooms/{room_id}/quickbook", handler.QuickBook)
+		r.Get("/admin/devices/rooms/{room_id}", device.Token)
+		return r
+	}
+	token := func(t *testing.T, r http.Handler, roomID string) string {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/devices/rooms/"+roomID, nil))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+		return body["token"]
+	}
+
+	t.Run("status", func(t *testing.T) {
+		next := models.Reservation{ID: 3, RoomID: "411", StartTime: time.Date(2030, 9, 1, 12, 0, 0, 0, time.UTC)}
+		service := mocks.NewReservationService(t)
+		service.On("GetRoomStatus", mock.Anything, "411").Return(&models.RoomStatus{RoomID: "411", Next: &next, FreeUntil: &next.StartTime}, nil)
+
+		w := httptest.NewRecorder()
+		newRouter(service, "secret").ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/rooms/411/status", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"occupied":false`)
+		assert.Contains(t, w.Body.String(), `"free_until":"2030-09-01T12:00:00Z"`)
+	})
+
+	t.Run("quick-book with the room token", func(t *testing.T) {
+		service := mocks.NewReservationService(t)
+		service.On("QuickBook", mock.Anything, "411", "device:411", 45*time.Minute).Return(&models.Reservation{ID: 8, RoomID: "411", Version: 2}, nil)
+		r := newRouter(service, "secret")
+
+		req := httptest.NewRequest(http.MethodPost, "/rooms/411/quickbook", strings.NewReader(`{"minutes":45,"user_id":"alice"}`))
+		req.Header.Set("Authorization", "Bearer "+token(t, r, "411"))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+
+	t.Run("token of another room is rejected", func(t *testing.T) {
+		r := newRouter(mocks.NewReservationService(t), "secret")
+
+		req := httptest.NewRequest(http.MethodPost, "/rooms/411/quickbook", nil)
+		req.Header.Set("Authorization", "Bearer "+token(t, r, "412"))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("devices disabled without a secret", func(t *testing.T) {
+		r := newRouter(mocks.NewReservationService(t), "")
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/rooms/411/quickbook", nil))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/devices/rooms/411", nil))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
+
+func TestReserveBatch(t *testing.T) {
+	body := `{"reservations":[
+		{"room_id":"b1","start_time":"2030-09-01T10:00:00Z","end_time":"2030-09-01T11:00:00Z"},
+		{"room_id":"b2","start_time":"2030-09-01T10:00:00Z","end_time":"2030-09-01T11:00:00Z"}
+	]}`
+
+	t.Run("booked", func(t *testing.T) {
+		service := mocks.NewReservationService(t)
+		service.On("CreateBatch", mock.Anything, mock.MatchedBy(func(reservations []models.Reservation) bool {
+			return len(reservations) == 2 && reservations[1].RoomID == "b2"
+		})).Run(func(args mock.Arguments) {
+			reservations := args.Get(1).([]models.Reservation)
+			reservations[0].ID, reservations[1].ID = 1, 2
+		}).Return(nil)
+		handler := handlers.NewReservationHandler(service)
+
+		w := httptest.NewRecorder()
+		handler.ReserveBatch(w, httptest.NewRequest(http.MethodPost, "/reservations/batch", strings.NewReader(body)))
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), `"id":2`)
+	})
+
+	t.Run("conflict reports every item", func(t *testing.T) {
+		service := mocks.NewReservationService(t)
+		service.On("CreateBatch", mock.Anything, mock.Anything).Return(&models.BatchError{Items: []models.BatchItemResult{
+			{Index: 0, Status: models.ImportAccepted},
+			{Index: 1, Status: models.ImportConflict, Code: "ROOM_CONFLICT", Conflicts: []models.Reservation{{ID: 9, RoomID: "b2"}}},
+		}})
+		handler := handlers.NewReservationHandler(service)
+
+		w := httptest.NewRecorder()
+		handler.ReserveBatch(w, httptest.NewRequest(http.MethodPost, "/reservations/batch", strings.NewReader(body)))
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+
+		var problem handlers.Problem
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&problem))
+		assert.Equal(t, "BATCH_CONFLICT", problem.Code)
+		require.Len(t, problem.Items, 2)
+		assert.Equal(t, models.ImportConflict, problem.Items[1].Status)
+		assert.Equal(t, 9, problem.Items[1].Conflicts[0].ID)
+	})
+
+	t.Run("invalid items", func(t *testing.T) {
+		service := mocks.NewReservationService(t)
+		service.On("CreateBatch", mock.Anything, mock.Anything).Return(&models.BatchError{Items: []models.BatchItemResult{
+			{Index: 0, Status: models.ImportInvalid, Code: "PAST_TIME", Field: "start_time"},
+		}})
+		handler := handlers.NewReservationHandler(service)
+
+		w := httptest.NewRecorder()
+		handler.ReserveBatch(w, httptest.NewRequest(http.MethodPost, "/reservations/batch", strings.NewReader(body)))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"code":"BATCH_INVALID"`)
+	})
+}
+
+func TestResources(t *testing.T) {
+	withParams := func(req *http.Request, params map[string]string) *http.Request {
+		rctx := chi.NewRouteContext()
+		for key, value := range params {
+			rctx.URLParams.Add(key, value)
+		}
+		return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	}
+
+	t.Run("search by attributes and interval", func(t *testing.T) {
+		from := time.Date(2030, 9, 1, 9, 0, 0, 0, time.UTC)
+		service := mocks.NewReservationService(t)
+		service.On("ListResources", mock.Anything, mock.MatchedBy(func(search models.ResourceQuery) bool {
+			return search.Type == models.ResourceDesk &&
+				len(search.Attributes) == 1 && search.Attributes["floor"] == "3" &&
+				search.StartTime.Equal(from) && search.EndTime.Equal(from.Add(8*time.Hour))
+		})).Return([]models.Room{{ID: "desk-12", Type: models.ResourceDesk, Attributes: map[string]string{"floor": "3"}}}, nil)
+		handler := handlers.NewReservationHandler(service)
+
+		req := httptest.NewRequest(http.MethodGet, "/resources/desk?floor=3&from=2030-09-01T09:00:00Z&to=2030-09-01T17:00:00Z", nil)
+		w := httptest.NewRecorder()
+		handler.ListResources(w, withParams(req, map[string]string{"type": "desk"}))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"room_id":"desk-12"`)
+		assert.Contains(t, w.Body.String(), `"attributes":{"floor":"3"}`)
+	})
+
+	t.Run("resource of another type", func(t *testing.T) {
+		service := mocks.NewReservationService(t)
+		service.On("GetResource", mock.Anything, models.ResourceVehicle, "aud-1").Return(nil, models.ErrResourceNotFound)
+		handler := handlers.NewReservationHandler(service)
+
+		req := httptest.NewRequest(http.MethodGet, "/resources/vehicle/aud-1", nil)
+		w := httptest.NewRecorder()
+		handler.GetResource(w, withParams(req, map[string]string{"type": "vehicle", "resource_id": "aud-1"}))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), `"code":"RESOURCE_NOT_FOUND"`)
+	})
+
+	t.Run("book a resource", func(t *testing.T) {
+		service := mocks.NewReservationService(t)
+		service.On("GetResource", mock.Anything, models.ResourceParking, "p-7").Return(&models.Room{ID: "p-7", Type: models.ResourceParking}, nil)
+		service.On("Create", mock.Anything, mock.MatchedBy(func(reservation *models.Reservation) bool {
+			return reservation.RoomID == "p-7"
+		})).Return(nil)
+		handler := handlers.NewReservationHandler(service)
+
+		body := `{"room_id":"other","start_time":"2030-09-01T09:00:00Z","end_time":"2030-09-01T17:00:00Z"}`
+		req := httptest.NewRequest(http.MethodPost, "/resources/parking/p-7/reservations", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		handler.ReserveResource(w, withParams(req, map[string]string{"type": "parking", "resource_id": "p-7"}))
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+
+	t.Run("update replaces attributes", func(t *testing.T) {
+		service := mocks.NewReservationService(t)
+		room := models.Room{ID: "car-1", Type: models.ResourceVehicle, Attributes: map[string]string{"seats": "5", "fuel": "diesel"}}
+		service.On("GetRoom", mock.Anything, "car-1").Return(&room, nil)
+		service.On("UpdateRoom", mock.Anything, mock.MatchedBy(func(room *models.Room) bool {
+			return room.Type == models.ResourceVehicle && len(room.Attributes) == 1 && room.Attributes["fuel"] == "electric"
+		})).Return(nil)
+		handler := handlers.NewReservationHandler(service)
+
+		body := `{"attributes":{"fuel":"electric"}}`
+		req := httptest.NewRequest(http.MethodPut, "/admin/rooms/car-1", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		handler.UpdateRoom(w, withParams(req, map[string]string{"room_id": "car-1"}))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
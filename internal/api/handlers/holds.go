@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+	"github.com/go-chi/chi/v5"
+)
+
+// CreateHold keeps an interval for ttl_seconds while the user completes the
+// booking.
+func (h *ReservationHandler) CreateHold(w http.ResponseWriter, r *http.Request) {
+	var hold models.Hold
+	if err := json.NewDecoder(r.Body).Decode(&hold); err != nil {
+		writeProblem(w, newProblem(r, http.StatusBadRequest, errInvalidBody))
+		return
+	}
+
+	if err := h.ReservationService.CreateHold(r.Context(), &hold); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated) //201
+	json.NewEncoder(w).Encode(hold)
+}
+
+func (h *ReservationHandler) GetHold(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.handleError(w, r, models.ErrHoldNotFound)
+		return
+	}
+
+	hold, err := h.ReservationService.GetHold(r.Context(), id)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK) //200
+	json.NewEncoder(w).Encode(hold)
+}
+
+// ConfirmHold turns an active hold into a reservation.
+func (h *ReservationHandler) ConfirmHold(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.handleError(w, r, models.ErrHoldNotFound)
+		return
+	}
+
+	reservation, err := h.ReservationService.ConfirmHold(r.Context(), id)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", reservationETag(reservation.Version))
+	w.WriteHeader(http.StatusCreated) //201
+	json.NewEncoder(w).Encode(reservation)
+}
+
+// ReleaseHold gives up an active hold before it expires.
+func (h *ReservationHandler) ReleaseHold(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.handleError(w, r, models.ErrHoldNotFound)
+		return
+	}
+
+	if err := h.ReservationService.ReleaseHold(r.Context(), id); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent) //204
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+)
+
+const (
+	idempotencyKeyHeader      = "Idempotency-Key"
+	idempotentReplayedHeader  = "Idempotent-Replayed"
+	maxIdempotentRequestBytes = 1 << 20
+)
+
+// Idempotency makes the wrapped handler safe to retry: the first response for
+// an Idempotency-Key is stored for ttl and replayed for later requests with the
+// same key, while reusing the key with a different body is rejected.
+// Requests without the header pass through unchanged. The stored response is
+// written even if the client has gone away, so a retry after a dropped
+// connection is replayed instead of being locked out.
+func Idempotency(storage models.IdempotencyStorage, ttl time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(idempotencyKeyHeader)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			body, err := io.ReadAll(io.LimitReader(r.Body, maxIdempotentRequestBytes+1))
+			if err != nil {
+				writeProblem(w, newProblem(r, http.StatusBadRequest, errInvalidBody))
+				return
+			}
+			if len(body) > maxIdempotentRequestBytes {
+				writeProblem(w, newProblem(r, http.StatusRequestEntityTooLarge, errBodyTooLarge))
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			hash := sha256.New()
+			hash.Write([]byte(r.Method + " " + r.URL.Path + "?" + r.URL.RawQuery + "\n"))
+			hash.Write([]byte("If-Match: " + r.Header.Get("If-Match") + "\n"))
+			hash.Write(body)
+
+			record := &models.IdempotencyRecord{
+				Key:         key,
+				Method:      r.Method,
+				Path:        r.URL.Path,
+				RequestHash: hex.EncodeToString(hash.Sum(nil)),
+				ExpiresAt:   time.Now().Add(ttl),
+			}
+
+			claimed, err := storage.Claim(r.Context(), record)
+			if err != nil {
+				writeProblem(w, newProblem(r, http.StatusInternalServerError, errInternal))
+				return
+			}
+			if !claimed {
+				replayIdempotent(w, r, storage, record)
+				return
+			}
+
+			recorder := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(recorder, r)
+
+			ctx := context.WithoutCancel(r.Context())
+			if recorder.status >= http.StatusInternalServerError {
+				err = storage.Release(ctx, record)
+			} else {
+				record.StatusCode = recorder.status
+				record.Headers = w.Header().Clone()
+				record.Body = recorder.body.Bytes()
+				err = storage.Complete(ctx, record)
+			}
+			if err != nil {
+				log.Printf("failed to store idempotency key %q: %v", key, err)
+			}
+		})
+	}
+}
+
+func replayIdempotent(w http.ResponseWriter, r *http.Request, storage models.IdempotencyStorage, record *models.IdempotencyRecord) {
+	stored, err := storage.Get(r.Context(), record.Key, record.Method, record.Path)
+	if err != nil {
+		writeProblem(w, newProblem(r, http.StatusInternalServerError, errInternal))
+		return
+	}
+
+	switch {
+	case stored == nil:
+		writeProblem(w, newProblem(r, http.StatusConflict, models.ErrIdempotencyKeyInProgress))
+	case stored.RequestHash != record.RequestHash:
+		writeProblem(w, newProblem(r, http.StatusUnprocessableEntity, models.ErrIdempotencyKeyReused))
+	case stored.StatusCode == 0:
+		writeProblem(w, newProblem(r, http.StatusConflict, models.ErrIdempotencyKeyInProgress))
+	default:
+		for name, values := range stored.Headers {
+			w.Header()[name] = values
+		}
+		w.Header().Set(idempotentReplayedHeader, "true")
+		w.WriteHeader(stored.StatusCode)
+		w.Write(stored.Body)
+	}
+}
+
+// responseRecorder passes the response through while keeping a copy of the
+// status code and body.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (rec *responseRecorder) WriteHeader(status int) {
+	rec.status = status
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *responseRecorder) Write(b []byte) (int, error) {
+	rec.body.Write(b)
+	return rec.ResponseWriter.Write(b)
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/importer"
+)
+
+const maxImportBytes = 10 << 20
+
+// ImportReservations bulk-loads reservations from a CSV or iCalendar body.
+// The format comes from the format query parameter or the Content-Type, and
+// dry_run=true reports per-row results without writing anything.
+func (h *ReservationHandler) ImportReservations(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = importer.FormatFromContentType(r.Header.Get("Content-Type"))
+	}
+
+	dryRun := false
+	if value := r.URL.Query().Get("dry_run"); value != "" {
+		var err error
+		dryRun, err = strconv.ParseBool(value)
+		if err != nil {
+			h.handleError(w, r, models.ErrInvalidParameter.WithField("dry_run"))
+			return
+		}
+	}
+
+	rows, err := importer.Parse(io.LimitReader(r.Body, maxImportBytes), format)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	report, err := h.ReservationService.Import(r.Context(), rows, dryRun)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK) //200
+	json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+	"github.com/go-chi/chi/v5"
+)
+
+// RoomStatus returns the compact view shown on door displays.
+func (h *ReservationHandler) RoomStatus(w http.ResponseWriter, r *http.Request) {
+	status, err := h.ReservationService.GetRoomStatus(r.Context(), chi.URLParam(r, "room_id"))
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK) //200
+	json.NewEncoder(w).Encode(status)
+}
+
+// QuickBook books the room from now for minutes, 30 by default. The body
+// is optional. The device token only identifies the room, so the booking is
+// made in the name of its display rather than of a user.
+func (h *ReservationHandler) QuickBook(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Minutes int `json:"minutes"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil && !errors.Is(err, io.EOF) {
+		writeProblem(w, newProblem(r, http.StatusBadRequest, errInvalidBody))
+		return
+	}
+	if request.Minutes < 0 {
+		h.handleError(w, r, models.ErrInvalidParameter.WithField("minutes"))
+		return
+	}
+
+	roomID := chi.URLParam(r, "room_id")
+	reservation, err := h.ReservationService.QuickBook(r.Context(), roomID, models.DeviceUserPrefix+roomID, time.Duration(request.Minutes)*time.Minute)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", reservationETag(reservation.Version))
+	w.WriteHeader(http.StatusCreated) //201
+	json.NewEncoder(w).Encode(reservation)
+}
+
+// DeviceHandler authenticates the displays mounted at room doors. Every
+// room has its own token, so a display can only book its room.
+type DeviceHandler struct {
+	Secret string
+}
+
+func NewDeviceHandler(secret string) *DeviceHandler {
+	return &DeviceHandler{
+		Secret: secret,
+	}
+}
+
+// Authorize rejects requests that do not carry "Authorization: Bearer
+// <token>" with the token of the room in the URL. An empty secret disables
+// the protected routes entirely.
+func (h *DeviceHandler) Authorize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		provided := r.Header.Get("Authorization")
+		expected := "Bearer " + deviceToken(h.Secret, chi.URLParam(r, "room_id"))
+		if h.Secret == "" || !hmac.Equal([]byte(provided), []byte(expected)) {
+			writeProblem(w, newProblem(r, http.StatusUnauthorized, models.ErrUnauthorized))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Token returns the device token of a room.
+func (h *DeviceHandler) Token(w http.ResponseWriter, r *http.Request) {
+	if h.Secret == "" {
+		writeProblem(w, newProblem(r, http.StatusForbidden, models.ErrDevicesDisabled))
+		return
+	}
+
+	roomID := chi.URLParam(r, "room_id")
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK) //200
+	json.NewEncoder(w).Encode(map[string]string{"room_id": roomID, "token": deviceToken(h.Secret, roomID)})
+}
+
+// deviceToken derives the token of a room's display like feedToken derives
+// feed tokens.
+func deviceToken(secret, roomID string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("device:" + roomID))
+	return hex.EncodeToString(mac.Sum(nil))[:32]
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+	"github.com/go-chi/chi/v5"
+)
+
+// CheckIn confirms that the reservation is being used, so it is not
+// released as a no-show. Kiosks and QR codes call it repeatedly, so a
+// second check-in succeeds without changing anything.
+func (h *ReservationHandler) CheckIn(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.handleError(w, r, models.ErrNoMatchingReservation)
+		return
+	}
+
+	reservation, err := h.ReservationService.CheckIn(r.Context(), id)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	h.writeReservation(w, reservation)
+}
+
+// EndReservation ends a meeting in progress now, freeing the rest of its
+// slot.
+func (h *ReservationHandler) EndReservation(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.handleError(w, r, models.ErrNoMatchingReservation)
+		return
+	}
+
+	reservation, err := h.ReservationService.EndReservation(r.Context(), id)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	h.writeReservation(w, reservation)
+}
+
+// ExtendReservation moves the end of a meeting by the minutes query
+// parameter, 15 minutes by default, if the room stays free.
+func (h *ReservationHandler) ExtendReservation(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.handleError(w, r, models.ErrNoMatchingReservation)
+		return
+	}
+
+	minutes := 0
+	if value := r.URL.Query().Get("minutes"); value != "" {
+		minutes, err = strconv.Atoi(value)
+		if err != nil || minutes <= 0 {
+			h.handleError(w, r, models.ErrInvalidParameter.WithField("minutes"))
+			return
+		}
+	}
+
+	reservation, err := h.ReservationService.ExtendReservation(r.Context(), id, time.Duration(minutes)*time.Minute)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	h.writeReservation(w, reservation)
+}
+
+func (h *ReservationHandler) writeReservation(w http.ResponseWriter, reservation *models.Reservation) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", reservationETag(reservation.Version))
+	w.WriteHeader(http.StatusOK) //200
+	json.NewEncoder(w).Encode(reservation)
+}
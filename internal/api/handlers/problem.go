@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+)
+
+const problemContentType = "application/problem+json"
+
+var (
+	errInvalidBody = &models.Error{Code: "INVALID_BODY", Message: "invalid body"}
+	errInternal    = &models.Error{Code: "INTERNAL", Message: "internal server error"}
+
+	errBodyTooLarge = &models.Error{Code: "BODY_TOO_LARGE", Message: "request body too large"}
+)
+
+// Problem is an RFC 7807 problem details object extended with a stable error
+// code, the offending field and, for conflicts, the overlapping reservations
+// together with suggested alternatives or the results of batch items.
+type Problem struct {
+	Type      string               `json:"type"`
+	Title     string               `json:"title"`
+	Status    int                  `json:"status"`
+	Detail    string               `json:"detail,omitempty"`
+	Instance  string               `json:"instance,omitempty"`
+	Code      string               `json:"code"`
+	Field     string               `json:"field,omitempty"`
+	Conflicts []models.Reservation `json:"conflicts,omitempty"`
+
+	SuggestedSlots []models.TimeSlot `json:"suggested_slots,omitempty"`
+	SuggestedRooms []string          `json:"suggested_rooms,omitempty"`
+
+	// Items reports every item of a rejected batch.
+	Items []models.BatchItemResult `json:"items,omitempty"`
+}
+
+func newProblem(r *http.Request, status int, err error) *Problem {
+	problem := &Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   err.Error(),
+		Instance: r.URL.Path,
+	}
+
+	var domainErr *models.Error
+	if errors.As(err, &domainErr) {
+		problem.Code = domainErr.Code
+		problem.Field = domainErr.Field
+	}
+
+	var conflictErr *models.ConflictError
+	if errors.As(err, &conflictErr) {
+		problem.Conflicts = conflictErr.Conflicts
+		problem.SuggestedSlots = conflictErr.SuggestedSlots
+		problem.SuggestedRooms = conflictErr.SuggestedRooms
+	}
+
+	var batchErr *models.BatchError
+	if errors.As(err, &batchErr) {
+		problem.Items = batchErr.Items
+	}
+
+	return problem
+}
+
+func writeProblem(w http.ResponseWriter, problem *Problem) {
+	w.Header().Set("Content-Type", problemContentType)
+	w.WriteHeader(problem.Status)
+	json.NewEncoder(w).Encode(problem)
+}
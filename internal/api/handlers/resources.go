@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+	"github.com/go-chi/chi/v5"
+)
+
+// ListResources lists the resources of the type in the path. Query
+// parameters other than from, to and limit are attributes the resources
+// must have; with from and to only resources free in between are listed.
+func (h *ReservationHandler) ListResources(w http.ResponseWriter, r *http.Request) {
+	search := models.ResourceQuery{
+		Type:       chi.URLParam(r, "type"),
+		Attributes: map[string]string{},
+	}
+
+	for name, values := range r.URL.Query() {
+		switch name {
+		case "from", "to":
+			t, err := time.Parse(time.RFC3339, values[0])
+			if err != nil {
+				h.handleError(w, r, models.ErrInvalidParameter.WithField(name))
+				return
+			}
+			if name == "from" {
+				search.StartTime = t
+			} else {
+				search.EndTime = t
+			}
+		case "limit":
+			limit, err := strconv.Atoi(values[0])
+			if err != nil || limit <= 0 {
+				h.handleError(w, r, models.ErrInvalidParameter.WithField("limit"))
+				return
+			}
+			search.Limit = limit
+		default:
+			search.Attributes[name] = values[0]
+		}
+	}
+
+	resources, err := h.ReservationService.ListResources(r.Context(), search)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK) //200
+	json.NewEncoder(w).Encode(resources)
+}
+
+func (h *ReservationHandler) GetResource(w http.ResponseWriter, r *http.Request) {
+	resource, err := h.ReservationService.GetResource(r.Context(), chi.URLParam(r, "type"), chi.URLParam(r, "resource_id"))
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK) //200
+	json.NewEncoder(w).Encode(resource)
+}
+
+// ReserveResource books the resource in the path like Reserve books a
+// room, after checking that the resource has the type in the path.
+func (h *ReservationHandler) ReserveResource(w http.ResponseWriter, r *http.Request) {
+	resource, err := h.ReservationService.GetResource(r.Context(), chi.URLParam(r, "type"), chi.URLParam(r, "resource_id"))
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	var reservation models.Reservation
+	if err := json.NewDecoder(r.Body).Decode(&reservation); err != nil {
+		writeProblem(w, newProblem(r, http.StatusBadRequest, errInvalidBody))
+		return
+	}
+	reservation.RoomID = resource.ID
+
+	if err := h.ReservationService.Create(r.Context(), &reservation); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", reservationETag(reservation.Version))
+	w.WriteHeader(http.StatusCreated) //201
+	json.NewEncoder(w).Encode(reservation)
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+	"github.com/go-chi/chi/v5"
+)
+
+// JoinWaitlist queues the user for a booked interval. With auto_book the
+// slot is booked for them as soon as it is freed, otherwise they get an
+// offer to claim.
+func (h *ReservationHandler) JoinWaitlist(w http.ResponseWriter, r *http.Request) {
+	var entry models.WaitlistEntry
+	if err := json.NewDecoder(r.Body).Decode(&entry); err != nil {
+		writeProblem(w, newProblem(r, http.StatusBadRequest, errInvalidBody))
+		return
+	}
+
+	if err := h.ReservationService.JoinWaitlist(r.Context(), &entry); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated) //201
+	json.NewEncoder(w).Encode(entry)
+}
+
+// ListWaitlist lists the current waitlist entries, optionally of one room
+// or user.
+func (h *ReservationHandler) ListWaitlist(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	entries, err := h.ReservationService.ListWaitlist(r.Context(), query.Get("room_id"), query.Get("user_id"))
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK) //200
+	json.NewEncoder(w).Encode(entries)
+}
+
+func (h *ReservationHandler) GetWaitlistEntry(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.handleError(w, r, models.ErrWaitlistEntryNotFound)
+		return
+	}
+
+	entry, err := h.ReservationService.GetWaitlistEntry(r.Context(), id)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK) //200
+	json.NewEncoder(w).Encode(entry)
+}
+
+// LeaveWaitlist withdraws an entry, declining its offer if it has one.
+func (h *ReservationHandler) LeaveWaitlist(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.handleError(w, r, models.ErrWaitlistEntryNotFound)
+		return
+	}
+
+	if err := h.ReservationService.LeaveWaitlist(r.Context(), id); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent) //204
+}
+
+// ClaimWaitlistOffer books the slot offered to a waitlist entry.
+func (h *ReservationHandler) ClaimWaitlistOffer(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.handleError(w, r, models.ErrWaitlistEntryNotFound)
+		return
+	}
+
+	reservation, err := h.ReservationService.ClaimWaitlistOffer(r.Context(), id)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", reservationETag(reservation.Version))
+	w.WriteHeader(http.StatusCreated) //201
+	json.NewEncoder(w).Encode(reservation)
+}
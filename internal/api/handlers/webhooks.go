@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+	"github.com/go-chi/chi/v5"
+)
+
+type WebhookHandler struct {
+	WebhookService models.WebhookService
+}
+
+func NewWebhookHandler(service models.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		WebhookService: service,
+	}
+}
+
+// CreateWebhook subscribes a URL to reservation events. The response is the
+// only place the signing secret is shown.
+func (h *WebhookHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	var webhook models.Webhook
+	if err := json.NewDecoder(r.Body).Decode(&webhook); err != nil {
+		writeProblem(w, newProblem(r, http.StatusBadRequest, errInvalidBody))
+		return
+	}
+
+	if err := h.WebhookService.CreateWebhook(r.Context(), &webhook); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated) //201
+	json.NewEncoder(w).Encode(webhook)
+}
+
+func (h *WebhookHandler) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	webhooks, err := h.WebhookService.ListWebhooks(r.Context())
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK) //200
+	json.NewEncoder(w).Encode(webhooks)
+}
+
+func (h *WebhookHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.handleError(w, r, models.ErrWebhookNotFound)
+		return
+	}
+
+	if err := h.WebhookService.DeleteWebhook(r.Context(), id); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent) //204
+}
+
+// ListDeliveries returns the delivery log of a webhook, newest first.
+func (h *WebhookHandler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.handleError(w, r, models.ErrWebhookNotFound)
+		return
+	}
+
+	limit := 0
+	if value := r.URL.Query().Get("limit"); value != "" {
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			h.handleError(w, r, models.ErrInvalidParameter.WithField("limit"))
+			return
+		}
+	}
+
+	deliveries, err := h.WebhookService.ListDeliveries(r.Context(), id, limit)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK) //200
+	json.NewEncoder(w).Encode(deliveries)
+}
+
+// RetryDelivery queues a delivery again, typically a dead one.
+func (h *WebhookHandler) RetryDelivery(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.handleError(w, r, models.ErrWebhookNotFound)
+		return
+	}
+	deliveryID, err := strconv.ParseInt(chi.URLParam(r, "delivery_id"), 10, 64)
+	if err != nil {
+		h.handleError(w, r, models.ErrWebhookNotFound)
+		return
+	}
+
+	if err := h.WebhookService.RetryDelivery(r.Context(), id, deliveryID); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted) //202
+}
+
+func (h *WebhookHandler) handleError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, models.ErrWebhookNotFound):
+		writeProblem(w, newProblem(r, http.StatusNotFound, err))
+	case errors.Is(err, models.ErrInvalidParameter):
+		writeProblem(w, newProblem(r, http.StatusBadRequest, err))
+	default:
+		writeProblem(w, newProblem(r, http.StatusInternalServerError, errInternal))
+	}
+}
@@ -1,28 +1,129 @@
 package routes
 
 import (
+	"log"
 	"net/http"
 	"time"
 
 	"github.com/Seven11Eleven/meeting_room_booking_system/internal/api/handlers"
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/config"
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
 	"github.com/Seven11Eleven/meeting_room_booking_system/internal/services"
 	"github.com/Seven11Eleven/meeting_room_booking_system/internal/storage/postgresql"
 	"github.com/go-chi/chi/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-func SetupRoutes(r *chi.Mux, timeout time.Duration, db *pgxpool.Pool) http.Handler {
+// idempotencyTTL is how long responses are kept for Idempotency-Key replays.
+const idempotencyTTL = 24 * time.Hour
+
+func SetupRoutes(r *chi.Mux, reservationService models.ReservationService, db *pgxpool.Pool, env *config.Config, events models.EventStream) http.Handler {
 	
 
-	reservationStorage := postgresql.NewStorage(db)
-	reservationService := services.NewReservationService(reservationStorage, timeout)
+	webhookService := services.NewWebhookService(postgresql.NewWebhookStorage(db))
+
+	loc, err := time.LoadLocation(env.CalendarTimeZone)
+	if err != nil {
+		log.Printf("Unknown calendar time zone %q, falling back to UTC: %v", env.CalendarTimeZone, err)
+		loc = time.UTC
+	}
 
 	handler := handlers.NewReservationHandler(reservationService)
+	handler.Location = loc
+
+	idempotent := handlers.Idempotency(postgresql.NewIdempotencyStorage(db), idempotencyTTL)
 
 	r.Route("/reservations", func(r chi.Router) {
 		r.Get("/{room_id}", handler.GetReservationsByRoom)
-		r.Post("/", handler.Reserve)
-		r.Delete("/", handler.CancelReserve)
+		r.With(idempotent).Post("/", handler.Reserve)
+		r.With(idempotent).Post("/batch", handler.ReserveBatch)
+		r.With(idempotent).Delete("/", handler.CancelReserve)
+
+		r.Get("/{room_id}/{id}", handler.GetReservation)
+		r.With(idempotent).Put("/{room_id}/{id}", handler.UpdateReservation)
+		r.With(idempotent).Delete("/{room_id}/{id}", handler.DeleteReservation)
+
+		r.Post("/{id}/checkin", handler.CheckIn)
+		r.Post("/{id}/end", handler.EndReservation)
+		r.Post("/{id}/extend", handler.ExtendReservation)
+	})
+
+	r.Route("/waitlist", func(r chi.Router) {
+		r.Get("/", handler.ListWaitlist)
+		r.With(idempotent).Post("/", handler.JoinWaitlist)
+
+		r.Get("/{id}", handler.GetWaitlistEntry)
+		r.Delete("/{id}", handler.LeaveWaitlist)
+		r.With(idempotent).Post("/{id}/claim", handler.ClaimWaitlistOffer)
+	})
+
+	r.Route("/holds", func(r chi.Router) {
+		r.With(idempotent).Post("/", handler.CreateHold)
+		r.Get("/{id}", handler.GetHold)
+		r.Delete("/{id}", handler.ReleaseHold)
+		r.With(idempotent).Post("/{id}/confirm", handler.ConfirmHold)
+	})
+
+	r.Route("/resources/{type}", func(r chi.Router) {
+		r.Get("/", handler.ListResources)
+		r.Get("/{resource_id}", handler.GetResource)
+		r.With(idempotent).Post("/{resource_id}/reservations", handler.ReserveResource)
+	})
+
+	r.Post("/freebusy", handler.FreeBusy)
+	r.With(handlers.AdminOnly(env.AdminToken)).Get("/changes", handler.Changes)
+
+	calendarHandler := handlers.NewCalendarHandler(reservationService, env.FeedSecret, loc)
+
+	eventsHandler := handlers.NewEventsHandler(events, env.FeedSecret, env.AdminToken)
+
+	r.With(handlers.AdminOnly(env.AdminToken)).Get("/events", eventsHandler.AllEvents)
+	r.Get("/rooms/{room_id}/events", eventsHandler.RoomEvents)
+
+	deviceHandler := handlers.NewDeviceHandler(env.DeviceSecret)
+
+	r.Get("/rooms/{room_id}/status", handler.RoomStatus)
+	r.With(deviceHandler.Authorize, idempotent).Post("/rooms/{room_id}/quickbook", handler.QuickBook)
+
+	r.Get("/rooms/{room_id}/calendar.ics", calendarHandler.RoomFeed)
+	r.Get("/users/{user_id}/calendar.ics", calendarHandler.UserFeed)
+
+	caldavHandler := handlers.NewCalDAVHandler(reservationService, loc)
+
+	chi.RegisterMethod("PROPFIND")
+	chi.RegisterMethod("REPORT")
+	r.Route("/caldav/rooms/{room_id}", func(r chi.Router) {
+		r.Options("/*", caldavHandler.Options)
+		r.MethodFunc("PROPFIND", "/", caldavHandler.PropfindCalendar)
+		r.MethodFunc("REPORT", "/", caldavHandler.Report)
+
+		r.MethodFunc("PROPFIND", "/{object}", caldavHandler.PropfindObject)
+		r.Get("/{object}", caldavHandler.GetObject)
+		r.Put("/{object}", caldavHandler.PutObject)
+		r.Delete("/{object}", caldavHandler.DeleteObject)
+	})
+
+	r.Route("/admin", func(r chi.Router) {
+		r.Use(handlers.AdminOnly(env.AdminToken))
+
+		r.Get("/feeds/rooms/{room_id}", calendarHandler.RoomFeedURL)
+		r.Get("/feeds/users/{user_id}", calendarHandler.UserFeedURL)
+		r.Get("/devices/rooms/{room_id}", deviceHandler.Token)
+
+		r.Post("/import", handler.ImportReservations)
+
+		r.Get("/rooms/{room_id}", handler.GetRoom)
+		r.Put("/rooms/{room_id}", handler.UpdateRoom)
+		r.Get("/approvals", handler.ListPending)
+		r.Post("/approvals/{id}/approve", handler.ApproveReservation)
+		r.Post("/approvals/{id}/reject", handler.RejectReservation)
+
+		webhookHandler := handlers.NewWebhookHandler(webhookService)
+		r.Post("/webhooks", webhookHandler.CreateWebhook)
+		r.Get("/webhooks", webhookHandler.ListWebhooks)
+		r.Delete("/webhooks/{id}", webhookHandler.DeleteWebhook)
+		r.Get("/webhooks/{id}/deliveries", webhookHandler.ListDeliveries)
+		r.Post("/webhooks/{id}/deliveries/{delivery_id}/retry", webhookHandler.RetryDelivery)
 	})
 
 	return r
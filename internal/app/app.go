@@ -5,10 +5,17 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/Seven11Eleven/meeting_room_booking_system/internal/api/routes"
 	"github.com/Seven11Eleven/meeting_room_booking_system/internal/config"
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/notify"
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/services"
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/sinks"
 	"github.com/Seven11Eleven/meeting_room_booking_system/internal/storage/postgresql"
 	"github.com/go-chi/chi/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -32,7 +39,30 @@ func NewApp( ctx context.Context ) (*App, error){
 
 	router := chi.NewRouter()
 
-	routes.SetupRoutes(router, 10*time.Second, db)
+	broker := services.NewEventBroker(postgresql.NewOutboxStorage(db))
+	go broker.Run(ctx)
+
+	reservationService := services.NewReservationService(postgresql.NewStorage(db), 10*time.Second)
+	sweeper := services.NewSweeper(reservationService)
+	sweeper.NoShowGrace = time.Duration(env.CheckInGraceMinutes) * time.Minute
+	go sweeper.Run(ctx)
+
+	routes.SetupRoutes(router, reservationService, db, env, broker)
+
+	sinks, err := outboxSinks(env, db)
+	if err != nil {
+		return nil, err
+	}
+	go services.NewOutboxRelay(postgresql.NewOutboxStorage(db), sinks...).Run(ctx)
+	go services.NewWebhookWorker(postgresql.NewWebhookStorage(db)).Run(ctx)
+
+	if env.SMTPAddr != "" {
+		worker, err := notificationWorker(env, db)
+		if err != nil {
+			return nil, err
+		}
+		go worker.Run(ctx)
+	}
 	
 
 	return &App{
@@ -60,4 +90,75 @@ func (a *App) Close() {
 		log.Printf("Error closing the database connection: %v", err)
 	}
 	log.Println("Server and database connection closed")
-}
\ No newline at end of file
+}
+
+// outboxSinks builds the sinks listed in OUTBOX_SINKS, webhook by default.
+// The email sink is added when SMTP_ADDR is set, since the SMTP worker only
+// sends what it queues.
+func outboxSinks(env *config.Config, db *pgxpool.Pool) ([]models.EventPublisher, error) {
+	names := env.OutboxSinks
+	if names == "" {
+		names = "webhook"
+	}
+
+	list := strings.Split(names, ",")
+	for i := range list {
+		list[i] = strings.TrimSpace(list[i])
+	}
+	if env.SMTPAddr != "" && !slices.Contains(list, "email") {
+		list = append(list, "email")
+	}
+
+	var sinkList []models.EventPublisher
+	for _, name := range list {
+		switch name {
+		case "webhook":
+			sinkList = append(sinkList, services.NewWebhookService(postgresql.NewWebhookStorage(db)))
+		case "stdout":
+			sinkList = append(sinkList, sinks.NewWriterSink(os.Stdout))
+		case "file":
+			f, err := os.OpenFile(env.OutboxFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+			if err != nil {
+				return nil, fmt.Errorf("failed to open outbox file: %w", err)
+			}
+			sinkList = append(sinkList, sinks.NewWriterSink(f))
+		case "nats":
+			subject := env.NATSSubject
+			if subject == "" {
+				subject = "reservations"
+			}
+			sink, err := sinks.NewNATSSink(env.NATSURL, subject)
+			if err != nil {
+				return nil, err
+			}
+			sinkList = append(sinkList, sink)
+		case "email":
+			if env.SMTPAddr == "" {
+				return nil, fmt.Errorf("the email outbox sink needs SMTP_ADDR")
+			}
+			reminderLead := time.Duration(env.ReminderMinutes) * time.Minute
+			sinkList = append(sinkList, services.NewNotificationService(postgresql.NewNotificationStorage(db), reminderLead, env.MailUserDomain))
+		default:
+			return nil, fmt.Errorf("unknown outbox sink %q", name)
+		}
+	}
+
+	return sinkList, nil
+}
+
+// notificationWorker sends the emails queued by the email sink through
+// SMTP_ADDR, rendered in the calendar time zone.
+func notificationWorker(env *config.Config, db *pgxpool.Pool) (*notify.Worker, error) {
+	loc, err := time.LoadLocation(env.CalendarTimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	renderer, err := notify.NewRenderer(env.MailTemplates, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	mailer := notify.NewSMTPMailer(env.SMTPAddr, env.MailFrom, env.SMTPUsername, env.SMTPPassword)
+	return notify.NewWorker(postgresql.NewNotificationStorage(db), mailer, renderer), nil
+}
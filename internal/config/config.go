@@ -11,6 +11,26 @@ type Config struct {
 	DBHost  string `mapstructure:"DATABASE_HOST"`
 	DBPort  string `mapstructure:"DATABASE_PORT"`
 	AppPort string `mapstructure:"PORT"`
+
+	AdminToken       string `mapstructure:"ADMIN_TOKEN"`
+	FeedSecret       string `mapstructure:"FEED_SECRET"`
+	CalendarTimeZone string `mapstructure:"CALENDAR_TIMEZONE"`
+	DeviceSecret     string `mapstructure:"DEVICE_SECRET"`
+
+	OutboxSinks string `mapstructure:"OUTBOX_SINKS"`
+	OutboxFile  string `mapstructure:"OUTBOX_FILE"`
+	NATSURL     string `mapstructure:"NATS_URL"`
+	NATSSubject string `mapstructure:"NATS_SUBJECT"`
+
+	SMTPAddr        string `mapstructure:"SMTP_ADDR"`
+	SMTPUsername    string `mapstructure:"SMTP_USERNAME"`
+	SMTPPassword    string `mapstructure:"SMTP_PASSWORD"`
+	MailFrom        string `mapstructure:"MAIL_FROM"`
+	MailUserDomain  string `mapstructure:"MAIL_USER_DOMAIN"`
+	MailTemplates   string `mapstructure:"MAIL_TEMPLATES"`
+	ReminderMinutes int    `mapstructure:"REMINDER_MINUTES"`
+
+	CheckInGraceMinutes int `mapstructure:"CHECKIN_GRACE_MINUTES"`
 }
 
 func MustLoad() *Config {
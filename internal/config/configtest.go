@@ -23,5 +23,8 @@ func LoadTestConfig() *Config {
 		DBHost: dbHost,
 		DBPort: dbPort,
 
+		AdminToken:       "testadmintoken",
+		FeedSecret:       "testfeedsecret",
+		CalendarTimeZone: "UTC",
 	}
 }
@@ -0,0 +1,35 @@
+package models
+
+// BatchItemResult is the outcome of one item of a rejected batch. Status
+// takes the import result values; an item that could have been booked on
+// its own is accepted. ConflictingItems lists the earlier items of the same
+// batch it overlaps.
+type BatchItemResult struct {
+	Index            int           `json:"index"`
+	Status           string        `json:"status"`
+	Code             string        `json:"code,omitempty"`
+	Field            string        `json:"field,omitempty"`
+	Detail           string        `json:"detail,omitempty"`
+	Conflicts        []Reservation `json:"conflicts,omitempty"`
+	ConflictingItems []int         `json:"conflicting_items,omitempty"`
+}
+
+// BatchError is returned when a batch is rejected, with the result of every
+// item. It unwraps to ErrBatchConflict when an item conflicts and to
+// ErrBatchInvalid otherwise.
+type BatchError struct {
+	Items []BatchItemResult
+}
+
+func (e *BatchError) Error() string {
+	return e.Unwrap().Error()
+}
+
+func (e *BatchError) Unwrap() error {
+	for _, item := range e.Items {
+		if item.Status == ImportConflict {
+			return ErrBatchConflict
+		}
+	}
+	return ErrBatchInvalid
+}
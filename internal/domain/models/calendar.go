@@ -0,0 +1,13 @@
+package models
+
+const (
+	RoomFeed = "room"
+	UserFeed = "user"
+)
+
+// CalendarFeed identifies the reservations published in one iCalendar
+// subscription: all bookings of a room or all bookings made by a user.
+type CalendarFeed struct {
+	Kind    string
+	Subject string
+}
@@ -0,0 +1,17 @@
+package models
+
+// Change is the state of a reservation after its latest write. Cancelled
+// reservations are kept as tombstones with Deleted set.
+type Change struct {
+	Seq         int64       `json:"seq"`
+	Deleted     bool        `json:"deleted"`
+	Reservation Reservation `json:"reservation"`
+}
+
+// ChangeFeed is a page of changes ordered by Seq. NextSince is the since
+// value for the next page.
+type ChangeFeed struct {
+	Changes   []Change `json:"changes"`
+	NextSince int64    `json:"next_since"`
+	HasMore   bool     `json:"has_more"`
+}
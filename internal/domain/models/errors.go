@@ -1,17 +1,94 @@
 package models
 
-import "errors"
+// Error is a domain error with a stable machine-readable code and, when the
+// error is caused by a single request field, the name of that field.
+type Error struct {
+	Code    string
+	Field   string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Is matches errors by code, so errors derived with WithField still match
+// their sentinel.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// WithField returns a copy of the error attributed to the given field.
+func (e *Error) WithField(field string) *Error {
+	return &Error{Code: e.Code, Field: field, Message: e.Message}
+}
 
 var (
 	// http status code - 409 Conflict
-	ErrRoomAlreadyReservated = errors.New("this room for this time is already reservated")
+	ErrRoomAlreadyReservated = &Error{Code: "ROOM_CONFLICT", Message: "this room for this time is already reservated"}
 
 	// http status code - 404 Not FOund
-	ErrNoMatchingReservation = errors.New("no matching reservation found")
+	ErrNoMatchingReservation = &Error{Code: "RESERVATION_NOT_FOUND", Message: "no matching reservation found"}
+	ErrWebhookNotFound       = &Error{Code: "WEBHOOK_NOT_FOUND", Message: "no matching webhook or delivery found"}
+	ErrWaitlistEntryNotFound = &Error{Code: "WAITLIST_ENTRY_NOT_FOUND", Message: "no matching waitlist entry found"}
+	ErrHoldNotFound          = &Error{Code: "HOLD_NOT_FOUND", Message: "no matching hold found"}
+	ErrResourceNotFound      = &Error{Code: "RESOURCE_NOT_FOUND", Message: "no matching resource found"}
 
 	// http status code - 400 Bad Request
-	ErrPastTime                      = errors.New("provided time must be in future")
-	ErrTimeNotProvided               = errors.New("start time and end time must be provded")
-	ErrEndTimeBeforeStartTime        = errors.New("end time must be after start time")
-	ErrReservationTimeExceedingLimit = errors.New("reservation duration cannot be more than 24 hours")
+	ErrPastTime                      = &Error{Code: "PAST_TIME", Field: "start_time", Message: "provided time must be in future"}
+	ErrTimeNotProvided               = &Error{Code: "TIME_NOT_PROVIDED", Message: "start time and end time must be provded"}
+	ErrEndTimeBeforeStartTime        = &Error{Code: "END_BEFORE_START", Field: "end_time", Message: "end time must be after start time"}
+	ErrReservationTimeExceedingLimit = &Error{Code: "DURATION_EXCEEDED", Field: "end_time", Message: "reservation duration cannot be more than 24 hours"}
+	ErrInvalidParameter              = &Error{Code: "INVALID_PARAMETER", Message: "invalid query parameter"}
+	ErrInvalidImportRow              = &Error{Code: "INVALID_ROW", Message: "import row cannot be parsed"}
+	ErrUnsupportedFormat             = &Error{Code: "UNSUPPORTED_FORMAT", Field: "format", Message: "unsupported import format"}
+	ErrBatchInvalid                  = &Error{Code: "BATCH_INVALID", Message: "some items of the batch are invalid, nothing was booked"}
+
+	// http status code - 409 Conflict
+	ErrIdempotencyKeyInProgress = &Error{Code: "IDEMPOTENCY_IN_PROGRESS", Message: "a request with this idempotency key is still being processed"}
+	ErrSlotAvailable            = &Error{Code: "SLOT_AVAILABLE", Message: "the room is free at this time and can be booked directly"}
+	ErrNoWaitlistOffer          = &Error{Code: "NO_WAITLIST_OFFER", Message: "the waitlist entry has no open offer to claim"}
+	ErrHoldNotActive            = &Error{Code: "HOLD_NOT_ACTIVE", Message: "the hold expired or was already confirmed or released"}
+	ErrReservationNotPending    = &Error{Code: "RESERVATION_NOT_PENDING", Message: "the reservation was already approved or is not awaiting approval"}
+	ErrBatchConflict            = &Error{Code: "BATCH_CONFLICT", Message: "some items of the batch overlap reservations or each other, nothing was booked"}
+	ErrReservationNotInProgress = &Error{Code: "RESERVATION_NOT_IN_PROGRESS", Message: "the reservation has not started yet or is already over"}
+	ErrAmbiguousReservation     = &Error{Code: "AMBIGUOUS_RESERVATION", Message: "several reservations match this interval, cancel by id instead"}
+	ErrCheckInNotOpen           = &Error{Code: "CHECKIN_NOT_OPEN", Message: "check-in is open from shortly before the start until the end of an approved reservation"}
+
+	// http status code - 401 Unauthorized
+	ErrUnauthorized = &Error{Code: "UNAUTHORIZED", Message: "missing or invalid credentials"}
+
+	// http status code - 403 Forbidden
+	ErrInvalidFeedToken = &Error{Code: "INVALID_FEED_TOKEN", Field: "token", Message: "calendar feed token is invalid"}
+	ErrDevicesDisabled  = &Error{Code: "DEVICES_DISABLED", Message: "device tokens are disabled until DEVICE_SECRET is set"}
+
+	// http status code - 406 Not Acceptable
+	ErrNotAcceptable = &Error{Code: "NOT_ACCEPTABLE", Message: "none of the accepted media types can be produced"}
+
+	// http status code - 412 Precondition Failed
+	ErrVersionMismatch = &Error{Code: "VERSION_MISMATCH", Message: "reservation was modified since the provided version"}
+
+	// http status code - 428 Precondition Required
+	ErrVersionNotProvided = &Error{Code: "PRECONDITION_REQUIRED", Message: "If-Match header with the reservation version must be provided"}
+
+	// http status code - 422 Unprocessable Entity
+	ErrIdempotencyKeyReused = &Error{Code: "IDEMPOTENCY_KEY_REUSED", Message: "idempotency key was already used with a different request"}
 )
+
+// ConflictError is returned when a reservation overlaps existing ones.
+// It unwraps to ErrRoomAlreadyReservated and carries the nearest free slots
+// of the same duration in the room and other rooms free at the requested time.
+type ConflictError struct {
+	Conflicts      []Reservation
+	SuggestedSlots []TimeSlot
+	SuggestedRooms []string
+}
+
+func (e *ConflictError) Error() string {
+	return ErrRoomAlreadyReservated.Error()
+}
+
+func (e *ConflictError) Unwrap() error {
+	return ErrRoomAlreadyReservated
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"context"
+	"time"
+)
+
+// Reservation lifecycle event types.
+const (
+	EventReservationCreated   = "reservation.created"
+	EventReservationUpdated   = "reservation.updated"
+	EventReservationCancelled = "reservation.cancelled"
+	EventReservationApproved  = "reservation.approved"
+	EventReservationRejected  = "reservation.rejected"
+	EventReservationCheckedIn = "reservation.checked_in"
+
+	// EventWaitlistOffered offers a freed slot to a waitlisted user. Its
+	// reservation is the one the user would get by claiming the offer.
+	EventWaitlistOffered = "waitlist.offered"
+)
+
+// EventTypes lists every event type subscribers can ask for.
+var EventTypes = []string{
+	EventReservationCreated, EventReservationUpdated, EventReservationCancelled,
+	EventReservationApproved, EventReservationRejected, EventReservationCheckedIn,
+	EventWaitlistOffered,
+}
+
+// ReservationEvent reports a change to a reservation, carrying its state
+// after the change. ID is the outbox position of the event; events are
+// delivered at least once, so consumers use it to drop duplicates. Seq is
+// the position of the event in event streams, zero until it is given one.
+type ReservationEvent struct {
+	ID          int64          `json:"id"`
+	Seq         int64          `json:"seq,omitempty"`
+	Type        string         `json:"type"`
+	OccurredAt  time.Time      `json:"occurred_at"`
+	Reservation Reservation    `json:"reservation"`
+	Waitlist    *WaitlistEntry `json:"waitlist,omitempty"`
+}
+
+// EventPublisher is a sink the outbox relay publishes reservation events to.
+// An error makes the relay retry the event, and every later event of the
+// same room, on its next run.
+type EventPublisher interface {
+	Publish(ctx context.Context, event ReservationEvent) error
+}
+
+// OutboxStorage gives access to the events written together with every
+// reservation change.
+type OutboxStorage interface {
+	// ClaimEvents leases up to limit unpublished events for lease and
+	// returns them oldest first. Events of a room are only claimed when none
+	// of its earlier events is leased, so each room is published in order.
+	// It returns nothing while another relay is claiming.
+	ClaimEvents(ctx context.Context, limit int, lease time.Duration) ([]ReservationEvent, error)
+	// MarkPublished records that every sink accepted the events.
+	MarkPublished(ctx context.Context, ids []int64) error
+	// LastSeq returns the stream position of the latest event.
+	LastSeq(ctx context.Context) (int64, error)
+	// EventsAfter returns up to limit events with a Seq above afterSeq, of
+	// one room or of all rooms when roomID is empty. Events of transactions
+	// that may still be followed by older ones are held back.
+	EventsAfter(ctx context.Context, afterSeq int64, roomID string, limit int) ([]ReservationEvent, error)
+	// Listen calls fn with the ID of every event committed from now on,
+	// until ctx is cancelled or the connection fails.
+	Listen(ctx context.Context, fn func(id int64)) error
+}
+
+// EventStream lets clients follow reservation events live and catch up on
+// the ones they missed.
+type EventStream interface {
+	// Subscribe returns a channel receiving the events of a room, or of all
+	// rooms when roomID is empty. The channel is closed when the subscriber
+	// falls behind or the stream loses events, the subscriber then resumes
+	// with EventsAfter. cancel must be called when done.
+	Subscribe(roomID string) (events <-chan ReservationEvent, cancel func())
+	EventsAfter(ctx context.Context, afterSeq int64, roomID string) ([]ReservationEvent, error)
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"encoding/base64"
+	"fmt"
+	"time"
+)
+
+// ReservationFilter narrows a room listing to reservations overlapping
+// [From, To), ordered by start time. A zero To leaves the range open ended
+// and After continues the listing from a previous page.
+type ReservationFilter struct {
+	From  time.Time
+	To    time.Time
+	Limit int
+	After *Cursor
+}
+
+// ListingInfo summarizes a listing page before it is streamed: a
+// fingerprint of the ids and versions it contains and the cursor of the
+// following page, if any.
+type ListingInfo struct {
+	Fingerprint string
+	NextCursor  string
+}
+
+// Cursor is a keyset pagination position: the start time and id of the last
+// reservation of the previous page.
+type Cursor struct {
+	StartTime time.Time
+	ID        int
+}
+
+// String encodes the cursor as an opaque URL-safe token.
+func (c Cursor) String() string {
+	raw := fmt.Sprintf("%d:%d", c.StartTime.UnixNano(), c.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// ParseCursor decodes a token produced by Cursor.String.
+func ParseCursor(token string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidParameter.WithField("cursor")
+	}
+
+	var nanos int64
+	var id int
+	if _, err := fmt.Sscanf(string(raw), "%d:%d", &nanos, &id); err != nil {
+		return nil, ErrInvalidParameter.WithField("cursor")
+	}
+
+	return &Cursor{StartTime: time.Unix(0, nanos).UTC(), ID: id}, nil
+}
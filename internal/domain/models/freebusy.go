@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// FreeBusyQuery asks for the busy time of several rooms within one window.
+type FreeBusyQuery struct {
+	RoomIDs   []string  `json:"room_ids"`
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+}
+
+// FreeBusy is the answer to a FreeBusyQuery, with one entry per requested
+// room in the order they were asked for.
+type FreeBusy struct {
+	StartTime time.Time  `json:"start_time"`
+	EndTime   time.Time  `json:"end_time"`
+	Rooms     []RoomBusy `json:"rooms"`
+}
+
+// RoomBusy lists the busy intervals of a room. Overlapping and adjacent
+// reservations are merged and intervals are clipped to the query window.
+type RoomBusy struct {
+	RoomID string     `json:"room_id"`
+	Busy   []TimeSlot `json:"busy"`
+}
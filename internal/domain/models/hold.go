@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// Hold states. An active hold blocks its interval like a reservation until
+// it is confirmed into one, released, or expires.
+const (
+	HoldActive    = "active"
+	HoldConfirmed = "confirmed"
+	HoldReleased  = "released"
+	HoldExpired   = "expired"
+)
+
+// Hold is a tentative reservation kept for TTLSeconds while the user
+// completes a booking.
+type Hold struct {
+	ID            int       `json:"id"`
+	RoomID        string    `json:"room_id"`
+	UserID        string    `json:"user_id,omitempty"`
+	StartTime     time.Time `json:"start_time"`
+	EndTime       time.Time `json:"end_time"`
+	TTLSeconds    int       `json:"ttl_seconds,omitempty"`
+	Status        string    `json:"status"`
+	ExpiresAt     time.Time `json:"expires_at"`
+	ReservationID int       `json:"reservation_id,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// Reservation is the reservation the hold turns into when confirmed.
+func (h Hold) Reservation() Reservation {
+	return Reservation{
+		RoomID:    h.RoomID,
+		UserID:    h.UserID,
+		StartTime: h.StartTime,
+		EndTime:   h.EndTime,
+	}
+}
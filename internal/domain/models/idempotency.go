@@ -0,0 +1,28 @@
+package models
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// IdempotencyRecord is a stored request identified by its Idempotency-Key
+// together with the response that was produced for it. StatusCode is zero
+// while the original request is still being processed.
+type IdempotencyRecord struct {
+	Key         string
+	Method      string
+	Path        string
+	RequestHash string
+	StatusCode  int
+	Headers     http.Header
+	Body        []byte
+	ExpiresAt   time.Time
+}
+
+type IdempotencyStorage interface {
+	Claim(ctx context.Context, record *IdempotencyRecord) (bool, error)
+	Get(ctx context.Context, key, method, path string) (*IdempotencyRecord, error)
+	Complete(ctx context.Context, record *IdempotencyRecord) error
+	Release(ctx context.Context, record *IdempotencyRecord) error
+}
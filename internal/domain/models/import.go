@@ -0,0 +1,32 @@
+package models
+
+const (
+	ImportAccepted = "accepted"
+	ImportConflict = "conflict"
+	ImportInvalid  = "invalid"
+)
+
+// ImportRow is one parsed input record. Err is set when the record could not
+// be turned into a reservation.
+type ImportRow struct {
+	Line        int
+	Reservation Reservation
+	Err         error
+}
+
+type ImportResult struct {
+	Line        int           `json:"line"`
+	Status      string        `json:"status"`
+	Reservation *Reservation  `json:"reservation,omitempty"`
+	Code        string        `json:"code,omitempty"`
+	Detail      string        `json:"detail,omitempty"`
+	Conflicts   []Reservation `json:"conflicts,omitempty"`
+}
+
+type ImportReport struct {
+	DryRun    bool           `json:"dry_run"`
+	Accepted  int            `json:"accepted"`
+	Conflicts int            `json:"conflicts"`
+	Invalid   int            `json:"invalid"`
+	Results   []ImportResult `json:"results"`
+}
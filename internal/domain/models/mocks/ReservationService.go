@@ -4,6 +4,7 @@ package mocks
 
 import (
 	context "context"
+	time "time"
 
 	models "github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
 	mock "github.com/stretchr/testify/mock"
@@ -14,6 +15,126 @@ type ReservationService struct {
 	mock.Mock
 }
 
+// ApproveReservation provides a mock function with given fields: ctx, id
+func (_m *ReservationService) ApproveReservation(ctx context.Context, id int) (*models.Reservation, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ApproveReservation")
+	}
+
+	var r0 *models.Reservation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*models.Reservation, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *models.Reservation); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Reservation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CheckIn provides a mock function with given fields: ctx, id
+func (_m *ReservationService) CheckIn(ctx context.Context, id int) (*models.Reservation, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CheckIn")
+	}
+
+	var r0 *models.Reservation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*models.Reservation, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *models.Reservation); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Reservation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ClaimWaitlistOffer provides a mock function with given fields: ctx, id
+func (_m *ReservationService) ClaimWaitlistOffer(ctx context.Context, id int) (*models.Reservation, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClaimWaitlistOffer")
+	}
+
+	var r0 *models.Reservation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*models.Reservation, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *models.Reservation); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Reservation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ConfirmHold provides a mock function with given fields: ctx, id
+func (_m *ReservationService) ConfirmHold(ctx context.Context, id int) (*models.Reservation, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConfirmHold")
+	}
+
+	var r0 *models.Reservation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*models.Reservation, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *models.Reservation); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Reservation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Create provides a mock function with given fields: ctx, reservation
 func (_m *ReservationService) Create(ctx context.Context, reservation *models.Reservation) error {
 	ret := _m.Called(ctx, reservation)
@@ -32,6 +153,60 @@ func (_m *ReservationService) Create(ctx context.Context, reservation *models.Re
 	return r0
 }
 
+// CreateBatch provides a mock function with given fields: ctx, reservations
+func (_m *ReservationService) CreateBatch(ctx context.Context, reservations []models.Reservation) error {
+	ret := _m.Called(ctx, reservations)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateBatch")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []models.Reservation) error); ok {
+		r0 = rf(ctx, reservations)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateHold provides a mock function with given fields: ctx, hold
+func (_m *ReservationService) CreateHold(ctx context.Context, hold *models.Hold) error {
+	ret := _m.Called(ctx, hold)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateHold")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Hold) error); ok {
+		r0 = rf(ctx, hold)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteByID provides a mock function with given fields: ctx, reservation
+func (_m *ReservationService) DeleteByID(ctx context.Context, reservation *models.Reservation) error {
+	ret := _m.Called(ctx, reservation)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteByID")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Reservation) error); ok {
+		r0 = rf(ctx, reservation)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteReservation provides a mock function with given fields: ctx, reservation
 func (_m *ReservationService) DeleteReservation(ctx context.Context, reservation *models.Reservation) error {
 	ret := _m.Called(ctx, reservation)
@@ -50,9 +225,165 @@ func (_m *ReservationService) DeleteReservation(ctx context.Context, reservation
 	return r0
 }
 
-// GetByRoomID provides a mock function with given fields: ctx, roomID
-func (_m *ReservationService) GetByRoomID(ctx context.Context, roomID string) (*models.RoomReservations, error) {
-	ret := _m.Called(ctx, roomID)
+// EndReservation provides a mock function with given fields: ctx, id
+func (_m *ReservationService) EndReservation(ctx context.Context, id int) (*models.Reservation, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EndReservation")
+	}
+
+	var r0 *models.Reservation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*models.Reservation, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *models.Reservation); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Reservation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ExpireHolds provides a mock function with given fields: ctx
+func (_m *ReservationService) ExpireHolds(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExpireHolds")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ExpireWaitlistOffers provides a mock function with given fields: ctx
+func (_m *ReservationService) ExpireWaitlistOffers(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExpireWaitlistOffers")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ExtendReservation provides a mock function with given fields: ctx, id, by
+func (_m *ReservationService) ExtendReservation(ctx context.Context, id int, by time.Duration) (*models.Reservation, error) {
+	ret := _m.Called(ctx, id, by)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExtendReservation")
+	}
+
+	var r0 *models.Reservation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Duration) (*models.Reservation, error)); ok {
+		return rf(ctx, id, by)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Duration) *models.Reservation); ok {
+		r0 = rf(ctx, id, by)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Reservation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, time.Duration) error); ok {
+		r1 = rf(ctx, id, by)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByICalUID provides a mock function with given fields: ctx, roomID, uid
+func (_m *ReservationService) GetByICalUID(ctx context.Context, roomID string, uid string) (*models.Reservation, error) {
+	ret := _m.Called(ctx, roomID, uid)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByICalUID")
+	}
+
+	var r0 *models.Reservation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*models.Reservation, error)); ok {
+		return rf(ctx, roomID, uid)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *models.Reservation); ok {
+		r0 = rf(ctx, roomID, uid)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Reservation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, roomID, uid)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByID provides a mock function with given fields: ctx, id
+func (_m *ReservationService) GetByID(ctx context.Context, id int) (*models.Reservation, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByID")
+	}
+
+	var r0 *models.Reservation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*models.Reservation, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *models.Reservation); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Reservation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByRoomID provides a mock function with given fields: ctx, roomID, filter
+func (_m *ReservationService) GetByRoomID(ctx context.Context, roomID string, filter models.ReservationFilter) (*models.RoomReservations, error) {
+	ret := _m.Called(ctx, roomID, filter)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetByRoomID")
@@ -60,19 +391,79 @@ func (_m *ReservationService) GetByRoomID(ctx context.Context, roomID string) (*
 
 	var r0 *models.RoomReservations
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.RoomReservations, error)); ok {
-		return rf(ctx, roomID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.ReservationFilter) (*models.RoomReservations, error)); ok {
+		return rf(ctx, roomID, filter)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) *models.RoomReservations); ok {
-		r0 = rf(ctx, roomID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.ReservationFilter) *models.RoomReservations); ok {
+		r0 = rf(ctx, roomID, filter)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*models.RoomReservations)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, roomID)
+	if rf, ok := ret.Get(1).(func(context.Context, string, models.ReservationFilter) error); ok {
+		r1 = rf(ctx, roomID, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCalendar provides a mock function with given fields: ctx, feed
+func (_m *ReservationService) GetCalendar(ctx context.Context, feed models.CalendarFeed) ([]models.Reservation, error) {
+	ret := _m.Called(ctx, feed)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCalendar")
+	}
+
+	var r0 []models.Reservation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.CalendarFeed) ([]models.Reservation, error)); ok {
+		return rf(ctx, feed)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.CalendarFeed) []models.Reservation); ok {
+		r0 = rf(ctx, feed)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Reservation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.CalendarFeed) error); ok {
+		r1 = rf(ctx, feed)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetChanges provides a mock function with given fields: ctx, since, limit
+func (_m *ReservationService) GetChanges(ctx context.Context, since int64, limit int) (*models.ChangeFeed, error) {
+	ret := _m.Called(ctx, since, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetChanges")
+	}
+
+	var r0 *models.ChangeFeed
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int) (*models.ChangeFeed, error)); ok {
+		return rf(ctx, since, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int) *models.ChangeFeed); ok {
+		r0 = rf(ctx, since, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.ChangeFeed)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int) error); ok {
+		r1 = rf(ctx, since, limit)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -80,6 +471,492 @@ func (_m *ReservationService) GetByRoomID(ctx context.Context, roomID string) (*
 	return r0, r1
 }
 
+// GetFreeBusy provides a mock function with given fields: ctx, query
+func (_m *ReservationService) GetFreeBusy(ctx context.Context, query models.FreeBusyQuery) (*models.FreeBusy, error) {
+	ret := _m.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetFreeBusy")
+	}
+
+	var r0 *models.FreeBusy
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.FreeBusyQuery) (*models.FreeBusy, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.FreeBusyQuery) *models.FreeBusy); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.FreeBusy)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.FreeBusyQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetHold provides a mock function with given fields: ctx, id
+func (_m *ReservationService) GetHold(ctx context.Context, id int) (*models.Hold, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetHold")
+	}
+
+	var r0 *models.Hold
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*models.Hold, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *models.Hold); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Hold)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetResource provides a mock function with given fields: ctx, resourceType, id
+func (_m *ReservationService) GetResource(ctx context.Context, resourceType string, id string) (*models.Room, error) {
+	ret := _m.Called(ctx, resourceType, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetResource")
+	}
+
+	var r0 *models.Room
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*models.Room, error)); ok {
+		return rf(ctx, resourceType, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *models.Room); ok {
+		r0 = rf(ctx, resourceType, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Room)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, resourceType, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRoom provides a mock function with given fields: ctx, roomID
+func (_m *ReservationService) GetRoom(ctx context.Context, roomID string) (*models.Room, error) {
+	ret := _m.Called(ctx, roomID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRoom")
+	}
+
+	var r0 *models.Room
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.Room, error)); ok {
+		return rf(ctx, roomID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.Room); ok {
+		r0 = rf(ctx, roomID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Room)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, roomID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRoomStatus provides a mock function with given fields: ctx, roomID
+func (_m *ReservationService) GetRoomStatus(ctx context.Context, roomID string) (*models.RoomStatus, error) {
+	ret := _m.Called(ctx, roomID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRoomStatus")
+	}
+
+	var r0 *models.RoomStatus
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.RoomStatus, error)); ok {
+		return rf(ctx, roomID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.RoomStatus); ok {
+		r0 = rf(ctx, roomID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.RoomStatus)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, roomID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWaitlistEntry provides a mock function with given fields: ctx, id
+func (_m *ReservationService) GetWaitlistEntry(ctx context.Context, id int) (*models.WaitlistEntry, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWaitlistEntry")
+	}
+
+	var r0 *models.WaitlistEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*models.WaitlistEntry, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *models.WaitlistEntry); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.WaitlistEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Import provides a mock function with given fields: ctx, rows, dryRun
+func (_m *ReservationService) Import(ctx context.Context, rows []models.ImportRow, dryRun bool) (*models.ImportReport, error) {
+	ret := _m.Called(ctx, rows, dryRun)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Import")
+	}
+
+	var r0 *models.ImportReport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []models.ImportRow, bool) (*models.ImportReport, error)); ok {
+		return rf(ctx, rows, dryRun)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []models.ImportRow, bool) *models.ImportReport); ok {
+		r0 = rf(ctx, rows, dryRun)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.ImportReport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []models.ImportRow, bool) error); ok {
+		r1 = rf(ctx, rows, dryRun)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// JoinWaitlist provides a mock function with given fields: ctx, entry
+func (_m *ReservationService) JoinWaitlist(ctx context.Context, entry *models.WaitlistEntry) error {
+	ret := _m.Called(ctx, entry)
+
+	if len(ret) == 0 {
+		panic("no return value specified for JoinWaitlist")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.WaitlistEntry) error); ok {
+		r0 = rf(ctx, entry)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// LeaveWaitlist provides a mock function with given fields: ctx, id
+func (_m *ReservationService) LeaveWaitlist(ctx context.Context, id int) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LeaveWaitlist")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ListPending provides a mock function with given fields: ctx, roomID
+func (_m *ReservationService) ListPending(ctx context.Context, roomID string) ([]models.Reservation, error) {
+	ret := _m.Called(ctx, roomID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPending")
+	}
+
+	var r0 []models.Reservation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]models.Reservation, error)); ok {
+		return rf(ctx, roomID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []models.Reservation); ok {
+		r0 = rf(ctx, roomID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Reservation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, roomID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListResources provides a mock function with given fields: ctx, query
+func (_m *ReservationService) ListResources(ctx context.Context, query models.ResourceQuery) ([]models.Room, error) {
+	ret := _m.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListResources")
+	}
+
+	var r0 []models.Room
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.ResourceQuery) ([]models.Room, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.ResourceQuery) []models.Room); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Room)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.ResourceQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListWaitlist provides a mock function with given fields: ctx, roomID, userID
+func (_m *ReservationService) ListWaitlist(ctx context.Context, roomID string, userID string) ([]models.WaitlistEntry, error) {
+	ret := _m.Called(ctx, roomID, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListWaitlist")
+	}
+
+	var r0 []models.WaitlistEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) ([]models.WaitlistEntry, error)); ok {
+		return rf(ctx, roomID, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) []models.WaitlistEntry); ok {
+		r0 = rf(ctx, roomID, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.WaitlistEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, roomID, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// QuickBook provides a mock function with given fields: ctx, roomID, userID, duration
+func (_m *ReservationService) QuickBook(ctx context.Context, roomID string, userID string, duration time.Duration) (*models.Reservation, error) {
+	ret := _m.Called(ctx, roomID, userID, duration)
+
+	if len(ret) == 0 {
+		panic("no return value specified for QuickBook")
+	}
+
+	var r0 *models.Reservation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Duration) (*models.Reservation, error)); ok {
+		return rf(ctx, roomID, userID, duration)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Duration) *models.Reservation); ok {
+		r0 = rf(ctx, roomID, userID, duration)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Reservation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, time.Duration) error); ok {
+		r1 = rf(ctx, roomID, userID, duration)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RejectReservation provides a mock function with given fields: ctx, id
+func (_m *ReservationService) RejectReservation(ctx context.Context, id int) (*models.Reservation, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RejectReservation")
+	}
+
+	var r0 *models.Reservation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*models.Reservation, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *models.Reservation); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Reservation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReleaseHold provides a mock function with given fields: ctx, id
+func (_m *ReservationService) ReleaseHold(ctx context.Context, id int) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleaseHold")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReleaseNoShows provides a mock function with given fields: ctx, grace
+func (_m *ReservationService) ReleaseNoShows(ctx context.Context, grace time.Duration) error {
+	ret := _m.Called(ctx, grace)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleaseNoShows")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Duration) error); ok {
+		r0 = rf(ctx, grace)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StreamByRoomID provides a mock function with given fields: ctx, roomID, filter, begin, fn
+func (_m *ReservationService) StreamByRoomID(ctx context.Context, roomID string, filter models.ReservationFilter, begin func(models.ListingInfo) error, fn func(models.Reservation) error) error {
+	ret := _m.Called(ctx, roomID, filter, begin, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StreamByRoomID")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.ReservationFilter, func(models.ListingInfo) error, func(models.Reservation) error) error); ok {
+		r0 = rf(ctx, roomID, filter, begin, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Update provides a mock function with given fields: ctx, reservation
+func (_m *ReservationService) Update(ctx context.Context, reservation *models.Reservation) error {
+	ret := _m.Called(ctx, reservation)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Reservation) error); ok {
+		r0 = rf(ctx, reservation)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateRoom provides a mock function with given fields: ctx, room
+func (_m *ReservationService) UpdateRoom(ctx context.Context, room *models.Room) error {
+	ret := _m.Called(ctx, room)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateRoom")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Room) error); ok {
+		r0 = rf(ctx, room)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewReservationService creates a new instance of ReservationService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewReservationService(t interface {
@@ -15,6 +15,60 @@ type ReservationStorage struct {
 	mock.Mock
 }
 
+// BookWaitlistEntry provides a mock function with given fields: ctx, entry, reservation
+func (_m *ReservationStorage) BookWaitlistEntry(ctx context.Context, entry *models.WaitlistEntry, reservation *models.Reservation) error {
+	ret := _m.Called(ctx, entry, reservation)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BookWaitlistEntry")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.WaitlistEntry, *models.Reservation) error); ok {
+		r0 = rf(ctx, entry, reservation)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CheckIn provides a mock function with given fields: ctx, reservation
+func (_m *ReservationStorage) CheckIn(ctx context.Context, reservation *models.Reservation) error {
+	ret := _m.Called(ctx, reservation)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CheckIn")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Reservation) error); ok {
+		r0 = rf(ctx, reservation)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ConfirmHold provides a mock function with given fields: ctx, hold, reservation
+func (_m *ReservationStorage) ConfirmHold(ctx context.Context, hold *models.Hold, reservation *models.Reservation) error {
+	ret := _m.Called(ctx, hold, reservation)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConfirmHold")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Hold, *models.Reservation) error); ok {
+		r0 = rf(ctx, hold, reservation)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Create provides a mock function with given fields: ctx, reservation
 func (_m *ReservationStorage) Create(ctx context.Context, reservation *models.Reservation) error {
 	ret := _m.Called(ctx, reservation)
@@ -33,6 +87,78 @@ func (_m *ReservationStorage) Create(ctx context.Context, reservation *models.Re
 	return r0
 }
 
+// CreateBatch provides a mock function with given fields: ctx, reservations
+func (_m *ReservationStorage) CreateBatch(ctx context.Context, reservations []models.Reservation) error {
+	ret := _m.Called(ctx, reservations)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateBatch")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []models.Reservation) error); ok {
+		r0 = rf(ctx, reservations)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateHold provides a mock function with given fields: ctx, hold
+func (_m *ReservationStorage) CreateHold(ctx context.Context, hold *models.Hold) error {
+	ret := _m.Called(ctx, hold)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateHold")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Hold) error); ok {
+		r0 = rf(ctx, hold)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateWaitlistEntry provides a mock function with given fields: ctx, entry
+func (_m *ReservationStorage) CreateWaitlistEntry(ctx context.Context, entry *models.WaitlistEntry) error {
+	ret := _m.Called(ctx, entry)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateWaitlistEntry")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.WaitlistEntry) error); ok {
+		r0 = rf(ctx, entry)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteByID provides a mock function with given fields: ctx, reservation
+func (_m *ReservationStorage) DeleteByID(ctx context.Context, reservation *models.Reservation) error {
+	ret := _m.Called(ctx, reservation)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteByID")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Reservation) error); ok {
+		r0 = rf(ctx, reservation)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteReservation provides a mock function with given fields: ctx, reservation
 func (_m *ReservationStorage) DeleteReservation(ctx context.Context, reservation *models.Reservation) error {
 	ret := _m.Called(ctx, reservation)
@@ -51,9 +177,159 @@ func (_m *ReservationStorage) DeleteReservation(ctx context.Context, reservation
 	return r0
 }
 
-// GetByRoomID provides a mock function with given fields: ctx, roomID
-func (_m *ReservationStorage) GetByRoomID(ctx context.Context, roomID string) (*models.RoomReservations, error) {
-	ret := _m.Called(ctx, roomID)
+// ExpireHolds provides a mock function with given fields: ctx
+func (_m *ReservationStorage) ExpireHolds(ctx context.Context) ([]models.Hold, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExpireHolds")
+	}
+
+	var r0 []models.Hold
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]models.Hold, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []models.Hold); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Hold)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ExpireWaitlistOffers provides a mock function with given fields: ctx
+func (_m *ReservationStorage) ExpireWaitlistOffers(ctx context.Context) ([]models.WaitlistEntry, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExpireWaitlistOffers")
+	}
+
+	var r0 []models.WaitlistEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]models.WaitlistEntry, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []models.WaitlistEntry); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.WaitlistEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBusy provides a mock function with given fields: ctx, roomIDs, startTime, endTime
+func (_m *ReservationStorage) GetBusy(ctx context.Context, roomIDs []string, startTime time.Time, endTime time.Time) (map[string][]models.TimeSlot, error) {
+	ret := _m.Called(ctx, roomIDs, startTime, endTime)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBusy")
+	}
+
+	var r0 map[string][]models.TimeSlot
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string, time.Time, time.Time) (map[string][]models.TimeSlot, error)); ok {
+		return rf(ctx, roomIDs, startTime, endTime)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string, time.Time, time.Time) map[string][]models.TimeSlot); ok {
+		r0 = rf(ctx, roomIDs, startTime, endTime)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string][]models.TimeSlot)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, roomIDs, startTime, endTime)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByICalUID provides a mock function with given fields: ctx, roomID, uid
+func (_m *ReservationStorage) GetByICalUID(ctx context.Context, roomID string, uid string) (*models.Reservation, error) {
+	ret := _m.Called(ctx, roomID, uid)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByICalUID")
+	}
+
+	var r0 *models.Reservation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*models.Reservation, error)); ok {
+		return rf(ctx, roomID, uid)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *models.Reservation); ok {
+		r0 = rf(ctx, roomID, uid)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Reservation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, roomID, uid)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByID provides a mock function with given fields: ctx, id
+func (_m *ReservationStorage) GetByID(ctx context.Context, id int) (*models.Reservation, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByID")
+	}
+
+	var r0 *models.Reservation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*models.Reservation, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *models.Reservation); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Reservation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByRoomID provides a mock function with given fields: ctx, roomID, filter
+func (_m *ReservationStorage) GetByRoomID(ctx context.Context, roomID string, filter models.ReservationFilter) (*models.RoomReservations, error) {
+	ret := _m.Called(ctx, roomID, filter)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetByRoomID")
@@ -61,19 +337,19 @@ func (_m *ReservationStorage) GetByRoomID(ctx context.Context, roomID string) (*
 
 	var r0 *models.RoomReservations
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.RoomReservations, error)); ok {
-		return rf(ctx, roomID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.ReservationFilter) (*models.RoomReservations, error)); ok {
+		return rf(ctx, roomID, filter)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) *models.RoomReservations); ok {
-		r0 = rf(ctx, roomID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.ReservationFilter) *models.RoomReservations); ok {
+		r0 = rf(ctx, roomID, filter)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*models.RoomReservations)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, roomID)
+	if rf, ok := ret.Get(1).(func(context.Context, string, models.ReservationFilter) error); ok {
+		r1 = rf(ctx, roomID, filter)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -81,23 +357,115 @@ func (_m *ReservationStorage) GetByRoomID(ctx context.Context, roomID string) (*
 	return r0, r1
 }
 
-// IsReserved provides a mock function with given fields: ctx, roomID, startTime, endTime
-func (_m *ReservationStorage) IsReserved(ctx context.Context, roomID string, startTime time.Time, endTime time.Time) (bool, error) {
+// GetCalendar provides a mock function with given fields: ctx, feed, since
+func (_m *ReservationStorage) GetCalendar(ctx context.Context, feed models.CalendarFeed, since time.Time) ([]models.Reservation, error) {
+	ret := _m.Called(ctx, feed, since)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCalendar")
+	}
+
+	var r0 []models.Reservation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.CalendarFeed, time.Time) ([]models.Reservation, error)); ok {
+		return rf(ctx, feed, since)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.CalendarFeed, time.Time) []models.Reservation); ok {
+		r0 = rf(ctx, feed, since)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Reservation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.CalendarFeed, time.Time) error); ok {
+		r1 = rf(ctx, feed, since)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetChanges provides a mock function with given fields: ctx, since, limit
+func (_m *ReservationStorage) GetChanges(ctx context.Context, since int64, limit int) ([]models.Change, error) {
+	ret := _m.Called(ctx, since, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetChanges")
+	}
+
+	var r0 []models.Change
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int) ([]models.Change, error)); ok {
+		return rf(ctx, since, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int) []models.Change); ok {
+		r0 = rf(ctx, since, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Change)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int) error); ok {
+		r1 = rf(ctx, since, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetFreeRooms provides a mock function with given fields: ctx, excludeRoomID, startTime, endTime, limit
+func (_m *ReservationStorage) GetFreeRooms(ctx context.Context, excludeRoomID string, startTime time.Time, endTime time.Time, limit int) ([]string, error) {
+	ret := _m.Called(ctx, excludeRoomID, startTime, endTime, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetFreeRooms")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Time, int) ([]string, error)); ok {
+		return rf(ctx, excludeRoomID, startTime, endTime, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Time, int) []string); ok {
+		r0 = rf(ctx, excludeRoomID, startTime, endTime, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time, time.Time, int) error); ok {
+		r1 = rf(ctx, excludeRoomID, startTime, endTime, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetHeld provides a mock function with given fields: ctx, roomID, startTime, endTime
+func (_m *ReservationStorage) GetHeld(ctx context.Context, roomID string, startTime time.Time, endTime time.Time) ([]models.Reservation, error) {
 	ret := _m.Called(ctx, roomID, startTime, endTime)
 
 	if len(ret) == 0 {
-		panic("no return value specified for IsReserved")
+		panic("no return value specified for GetHeld")
 	}
 
-	var r0 bool
+	var r0 []models.Reservation
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Time) (bool, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Time) ([]models.Reservation, error)); ok {
 		return rf(ctx, roomID, startTime, endTime)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Time) bool); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Time) []models.Reservation); ok {
 		r0 = rf(ctx, roomID, startTime, endTime)
 	} else {
-		r0 = ret.Get(0).(bool)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Reservation)
+		}
 	}
 
 	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time, time.Time) error); ok {
@@ -109,6 +477,482 @@ func (_m *ReservationStorage) IsReserved(ctx context.Context, roomID string, sta
 	return r0, r1
 }
 
+// GetHold provides a mock function with given fields: ctx, id
+func (_m *ReservationStorage) GetHold(ctx context.Context, id int) (*models.Hold, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetHold")
+	}
+
+	var r0 *models.Hold
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*models.Hold, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *models.Hold); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Hold)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOverlapping provides a mock function with given fields: ctx, roomID, startTime, endTime
+func (_m *ReservationStorage) GetOverlapping(ctx context.Context, roomID string, startTime time.Time, endTime time.Time) ([]models.Reservation, error) {
+	ret := _m.Called(ctx, roomID, startTime, endTime)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOverlapping")
+	}
+
+	var r0 []models.Reservation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Time) ([]models.Reservation, error)); ok {
+		return rf(ctx, roomID, startTime, endTime)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Time) []models.Reservation); ok {
+		r0 = rf(ctx, roomID, startTime, endTime)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Reservation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, roomID, startTime, endTime)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRoom provides a mock function with given fields: ctx, roomID
+func (_m *ReservationStorage) GetRoom(ctx context.Context, roomID string) (*models.Room, error) {
+	ret := _m.Called(ctx, roomID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRoom")
+	}
+
+	var r0 *models.Room
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.Room, error)); ok {
+		return rf(ctx, roomID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.Room); ok {
+		r0 = rf(ctx, roomID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Room)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, roomID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWaitlistCandidates provides a mock function with given fields: ctx, roomID, startTime, endTime
+func (_m *ReservationStorage) GetWaitlistCandidates(ctx context.Context, roomID string, startTime time.Time, endTime time.Time) ([]models.WaitlistEntry, error) {
+	ret := _m.Called(ctx, roomID, startTime, endTime)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWaitlistCandidates")
+	}
+
+	var r0 []models.WaitlistEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Time) ([]models.WaitlistEntry, error)); ok {
+		return rf(ctx, roomID, startTime, endTime)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Time) []models.WaitlistEntry); ok {
+		r0 = rf(ctx, roomID, startTime, endTime)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.WaitlistEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, roomID, startTime, endTime)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWaitlistEntry provides a mock function with given fields: ctx, id
+func (_m *ReservationStorage) GetWaitlistEntry(ctx context.Context, id int) (*models.WaitlistEntry, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWaitlistEntry")
+	}
+
+	var r0 *models.WaitlistEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*models.WaitlistEntry, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *models.WaitlistEntry); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.WaitlistEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IsHeld provides a mock function with given fields: ctx, roomID, startTime, endTime
+func (_m *ReservationStorage) IsHeld(ctx context.Context, roomID string, startTime time.Time, endTime time.Time) (bool, error) {
+	ret := _m.Called(ctx, roomID, startTime, endTime)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsHeld")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Time) (bool, error)); ok {
+		return rf(ctx, roomID, startTime, endTime)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Time) bool); ok {
+		r0 = rf(ctx, roomID, startTime, endTime)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, roomID, startTime, endTime)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IsReserved provides a mock function with given fields: ctx, roomID, startTime, endTime
+func (_m *ReservationStorage) IsReserved(ctx context.Context, roomID string, startTime time.Time, endTime time.Time) (bool, error) {
+	ret := _m.Called(ctx, roomID, startTime, endTime)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsReserved")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Time) (bool, error)); ok {
+		return rf(ctx, roomID, startTime, endTime)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Time) bool); ok {
+		r0 = rf(ctx, roomID, startTime, endTime)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, roomID, startTime, endTime)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListPending provides a mock function with given fields: ctx, roomID
+func (_m *ReservationStorage) ListPending(ctx context.Context, roomID string) ([]models.Reservation, error) {
+	ret := _m.Called(ctx, roomID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPending")
+	}
+
+	var r0 []models.Reservation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]models.Reservation, error)); ok {
+		return rf(ctx, roomID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []models.Reservation); ok {
+		r0 = rf(ctx, roomID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Reservation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, roomID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListResources provides a mock function with given fields: ctx, query
+func (_m *ReservationStorage) ListResources(ctx context.Context, query models.ResourceQuery) ([]models.Room, error) {
+	ret := _m.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListResources")
+	}
+
+	var r0 []models.Room
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.ResourceQuery) ([]models.Room, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.ResourceQuery) []models.Room); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Room)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.ResourceQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListWaitlist provides a mock function with given fields: ctx, roomID, userID
+func (_m *ReservationStorage) ListWaitlist(ctx context.Context, roomID string, userID string) ([]models.WaitlistEntry, error) {
+	ret := _m.Called(ctx, roomID, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListWaitlist")
+	}
+
+	var r0 []models.WaitlistEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) ([]models.WaitlistEntry, error)); ok {
+		return rf(ctx, roomID, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) []models.WaitlistEntry); ok {
+		r0 = rf(ctx, roomID, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.WaitlistEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, roomID, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OfferWaitlistEntry provides a mock function with given fields: ctx, entry, expiresAt
+func (_m *ReservationStorage) OfferWaitlistEntry(ctx context.Context, entry *models.WaitlistEntry, expiresAt time.Time) error {
+	ret := _m.Called(ctx, entry, expiresAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for OfferWaitlistEntry")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.WaitlistEntry, time.Time) error); ok {
+		r0 = rf(ctx, entry, expiresAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReleaseHold provides a mock function with given fields: ctx, id
+func (_m *ReservationStorage) ReleaseHold(ctx context.Context, id int) (*models.Hold, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleaseHold")
+	}
+
+	var r0 *models.Hold
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*models.Hold, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *models.Hold); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Hold)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReleaseNoShows provides a mock function with given fields: ctx, startedBefore
+func (_m *ReservationStorage) ReleaseNoShows(ctx context.Context, startedBefore time.Time) ([]models.Reservation, error) {
+	ret := _m.Called(ctx, startedBefore)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleaseNoShows")
+	}
+
+	var r0 []models.Reservation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) ([]models.Reservation, error)); ok {
+		return rf(ctx, startedBefore)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) []models.Reservation); ok {
+		r0 = rf(ctx, startedBefore)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Reservation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, startedBefore)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveRoom provides a mock function with given fields: ctx, room
+func (_m *ReservationStorage) SaveRoom(ctx context.Context, room *models.Room) error {
+	ret := _m.Called(ctx, room)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveRoom")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Room) error); ok {
+		r0 = rf(ctx, room)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetStatus provides a mock function with given fields: ctx, reservation, status
+func (_m *ReservationStorage) SetStatus(ctx context.Context, reservation *models.Reservation, status string) error {
+	ret := _m.Called(ctx, reservation, status)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetStatus")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Reservation, string) error); ok {
+		r0 = rf(ctx, reservation, status)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StreamByRoomID provides a mock function with given fields: ctx, roomID, filter, begin, fn
+func (_m *ReservationStorage) StreamByRoomID(ctx context.Context, roomID string, filter models.ReservationFilter, begin func(models.ListingInfo) error, fn func(models.Reservation) error) error {
+	ret := _m.Called(ctx, roomID, filter, begin, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StreamByRoomID")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.ReservationFilter, func(models.ListingInfo) error, func(models.Reservation) error) error); ok {
+		r0 = rf(ctx, roomID, filter, begin, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Update provides a mock function with given fields: ctx, reservation
+func (_m *ReservationStorage) Update(ctx context.Context, reservation *models.Reservation) error {
+	ret := _m.Called(ctx, reservation)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Reservation) error); ok {
+		r0 = rf(ctx, reservation)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// WithdrawWaitlistEntry provides a mock function with given fields: ctx, id
+func (_m *ReservationStorage) WithdrawWaitlistEntry(ctx context.Context, id int) (*models.WaitlistEntry, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithdrawWaitlistEntry")
+	}
+
+	var r0 *models.WaitlistEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*models.WaitlistEntry, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *models.WaitlistEntry); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.WaitlistEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewReservationStorage creates a new instance of ReservationStorage. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewReservationStorage(t interface {
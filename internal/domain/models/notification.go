@@ -0,0 +1,59 @@
+package models
+
+import (
+	"context"
+	"time"
+)
+
+// NotificationReminder is the kind of a reminder sent ahead of a
+// reservation. Other notifications are of the kind of the reservation event
+// that caused them.
+const NotificationReminder = "reservation.reminder"
+
+// Notification is an email about a reservation queued for one recipient.
+// It goes through the same states as a webhook delivery. EventID is the
+// outbox event it was made for and is zero for reminders. Waitlist is set
+// for waitlist offers.
+type Notification struct {
+	ID          int64          `json:"id"`
+	EventID     int64          `json:"event_id,omitempty"`
+	Kind        string         `json:"kind"`
+	Recipient   string         `json:"recipient"`
+	Reservation Reservation    `json:"reservation"`
+	Waitlist    *WaitlistEntry `json:"waitlist,omitempty"`
+	Status      string         `json:"status"`
+	Attempts    int            `json:"attempts"`
+	SendAt      time.Time      `json:"send_at"`
+	LastError   string         `json:"last_error,omitempty"`
+	CreatedAt   time.Time      `json:"created_at"`
+	SentAt      *time.Time     `json:"sent_at,omitempty"`
+}
+
+type NotificationStorage interface {
+	// Enqueue queues a notification unless one of the same kind was already
+	// queued for the event.
+	Enqueue(ctx context.Context, notification *Notification) error
+	// ScheduleReminder replaces the pending reminder of the reservation.
+	ScheduleReminder(ctx context.Context, notification *Notification) error
+	CancelReminder(ctx context.Context, reservationID int) error
+	ClaimNotifications(ctx context.Context, limit int, lease time.Duration) ([]Notification, error)
+	CompleteNotification(ctx context.Context, notification *Notification) error
+}
+
+// Mail is an email with optional attachments.
+type Mail struct {
+	To          []string
+	Subject     string
+	Body        string
+	Attachments []MailAttachment
+}
+
+type MailAttachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+type Mailer interface {
+	Send(ctx context.Context, mail Mail) error
+}
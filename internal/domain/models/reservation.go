@@ -5,11 +5,33 @@ import (
 	"time"
 )
 
+// Reservation states. Reservations of rooms that require approval start
+// pending until an approver approves or rejects them, all others are
+// approved right away. Rejected and cancelled reservations free their slot.
+const (
+	StatusPending   = "pending"
+	StatusApproved  = "approved"
+	StatusRejected  = "rejected"
+	StatusCancelled = "cancelled"
+)
+
 type Reservation struct {
-	ID        int       `json:"id"`
-	RoomID    string    `json:"room_id"`
-	StartTime time.Time `json:"start_time"`
-	EndTime   time.Time `json:"end_time"`
+	ID          int        `json:"id"`
+	RoomID      string     `json:"room_id"`
+	UserID      string     `json:"user_id,omitempty"`
+	ICalUID     string     `json:"ical_uid,omitempty"`
+	StartTime   time.Time  `json:"start_time"`
+	EndTime     time.Time  `json:"end_time"`
+	Status      string     `json:"status"`
+	Version     int        `json:"version"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	CancelledAt *time.Time `json:"cancelled_at,omitempty"`
+	CheckedInAt *time.Time `json:"checked_in_at,omitempty"`
+	// NoShow marks reservations released because nobody checked in.
+	NoShow bool `json:"no_show,omitempty"`
+	// Seats is the number of seats booked in a room with a capacity. It
+	// defaults to one; rooms without a capacity are booked as a whole.
+	Seats int `json:"seats,omitempty"`
 }
 
 type TimeSlot struct {
@@ -18,19 +40,127 @@ type TimeSlot struct {
 }
 
 type RoomReservations struct {
-	RoomID       string     `json:"room_id"`
-	Reservations []TimeSlot `json:"reservations"`
+	RoomID       string        `json:"room_id"`
+	Reservations []Reservation `json:"reservations"`
+	NextCursor   string        `json:"next_cursor,omitempty"`
 }
 
 type ReservationService interface {
 	Create(ctx context.Context, reservation *Reservation) error
 	DeleteReservation(ctx context.Context, reservation *Reservation) error
-	GetByRoomID(ctx context.Context, roomID string) (*RoomReservations, error)
+	GetByRoomID(ctx context.Context, roomID string, filter ReservationFilter) (*RoomReservations, error)
+	StreamByRoomID(ctx context.Context, roomID string, filter ReservationFilter, begin func(ListingInfo) error, fn func(Reservation) error) error
+	GetByID(ctx context.Context, id int) (*Reservation, error)
+	GetByICalUID(ctx context.Context, roomID, uid string) (*Reservation, error)
+	Update(ctx context.Context, reservation *Reservation) error
+	DeleteByID(ctx context.Context, reservation *Reservation) error
+	GetCalendar(ctx context.Context, feed CalendarFeed) ([]Reservation, error)
+	GetFreeBusy(ctx context.Context, query FreeBusyQuery) (*FreeBusy, error)
+	GetChanges(ctx context.Context, since int64, limit int) (*ChangeFeed, error)
+	JoinWaitlist(ctx context.Context, entry *WaitlistEntry) error
+	GetWaitlistEntry(ctx context.Context, id int) (*WaitlistEntry, error)
+	ListWaitlist(ctx context.Context, roomID, userID string) ([]WaitlistEntry, error)
+	LeaveWaitlist(ctx context.Context, id int) error
+	ClaimWaitlistOffer(ctx context.Context, id int) (*Reservation, error)
+	ExpireWaitlistOffers(ctx context.Context) error
+	CreateHold(ctx context.Context, hold *Hold) error
+	GetHold(ctx context.Context, id int) (*Hold, error)
+	ConfirmHold(ctx context.Context, id int) (*Reservation, error)
+	ReleaseHold(ctx context.Context, id int) error
+	ExpireHolds(ctx context.Context) error
+	Import(ctx context.Context, rows []ImportRow, dryRun bool) (*ImportReport, error)
+	GetRoom(ctx context.Context, roomID string) (*Room, error)
+	UpdateRoom(ctx context.Context, room *Room) error
+	ListPending(ctx context.Context, roomID string) ([]Reservation, error)
+	// GetResource returns a resource of the given type.
+	GetResource(ctx context.Context, resourceType, id string) (*Room, error)
+	ListResources(ctx context.Context, query ResourceQuery) ([]Room, error)
+	ApproveReservation(ctx context.Context, id int) (*Reservation, error)
+	RejectReservation(ctx context.Context, id int) (*Reservation, error)
+	CheckIn(ctx context.Context, id int) (*Reservation, error)
+	// ReleaseNoShows cancels the reservations nobody checked in to within
+	// grace of their start.
+	ReleaseNoShows(ctx context.Context, grace time.Duration) error
+	// EndReservation ends a reservation in progress now.
+	EndReservation(ctx context.Context, id int) (*Reservation, error)
+	// ExtendReservation moves the end of a reservation that is not over by
+	// the given duration, 15 minutes when zero.
+	ExtendReservation(ctx context.Context, id int, by time.Duration) (*Reservation, error)
+	GetRoomStatus(ctx context.Context, roomID string) (*RoomStatus, error)
+	// QuickBook books a room from now for the given duration and checks the
+	// booking in.
+	QuickBook(ctx context.Context, roomID, userID string, duration time.Duration) (*Reservation, error)
+	// CreateBatch books every reservation or none of them.
+	CreateBatch(ctx context.Context, reservations []Reservation) error
 }
 
 type ReservationStorage interface {
 	Create(ctx context.Context, reservation *Reservation) error
 	DeleteReservation(ctx context.Context, reservation *Reservation) error
-	GetByRoomID(ctx context.Context, roomID string) (*RoomReservations, error)
+	GetByRoomID(ctx context.Context, roomID string, filter ReservationFilter) (*RoomReservations, error)
+	// StreamByRoomID summarizes the page for begin and then streams it to fn
+	// from the same snapshot, so the summary describes the streamed rows.
+	StreamByRoomID(ctx context.Context, roomID string, filter ReservationFilter, begin func(ListingInfo) error, fn func(Reservation) error) error
+	GetByID(ctx context.Context, id int) (*Reservation, error)
+	GetByICalUID(ctx context.Context, roomID, uid string) (*Reservation, error)
+	Update(ctx context.Context, reservation *Reservation) error
+	DeleteByID(ctx context.Context, reservation *Reservation) error
 	IsReserved(ctx context.Context, roomID string, startTime, endTime time.Time) (bool, error)
+	// IsHeld reports whether an active hold or an open waitlist offer
+	// overlaps the interval. IsReserved counts them too.
+	IsHeld(ctx context.Context, roomID string, startTime, endTime time.Time) (bool, error)
+	// GetHeld returns the active holds and open waitlist offers overlapping
+	// the interval as one-seat reservations.
+	GetHeld(ctx context.Context, roomID string, startTime, endTime time.Time) ([]Reservation, error)
+	GetOverlapping(ctx context.Context, roomID string, startTime, endTime time.Time) ([]Reservation, error)
+	GetFreeRooms(ctx context.Context, excludeRoomID string, startTime, endTime time.Time, limit int) ([]string, error)
+	GetCalendar(ctx context.Context, feed CalendarFeed, since time.Time) ([]Reservation, error)
+	GetBusy(ctx context.Context, roomIDs []string, startTime time.Time, endTime time.Time) (map[string][]TimeSlot, error)
+	GetChanges(ctx context.Context, since int64, limit int) ([]Change, error)
+	CreateWaitlistEntry(ctx context.Context, entry *WaitlistEntry) error
+	GetWaitlistEntry(ctx context.Context, id int) (*WaitlistEntry, error)
+	ListWaitlist(ctx context.Context, roomID, userID string) ([]WaitlistEntry, error)
+	// WithdrawWaitlistEntry withdraws a waiting or offered entry and returns
+	// it as it was before.
+	WithdrawWaitlistEntry(ctx context.Context, id int) (*WaitlistEntry, error)
+	// GetWaitlistCandidates returns the waiting entries overlapping an
+	// interval, first come first.
+	GetWaitlistCandidates(ctx context.Context, roomID string, startTime, endTime time.Time) ([]WaitlistEntry, error)
+	OfferWaitlistEntry(ctx context.Context, entry *WaitlistEntry, expiresAt time.Time) error
+	// BookWaitlistEntry creates the reservation of a waiting entry or of an
+	// unexpired offer and marks the entry booked.
+	BookWaitlistEntry(ctx context.Context, entry *WaitlistEntry, reservation *Reservation) error
+	// ExpireWaitlistOffers expires the offers that ran out and returns them.
+	ExpireWaitlistOffers(ctx context.Context) ([]WaitlistEntry, error)
+	CreateHold(ctx context.Context, hold *Hold) error
+	GetHold(ctx context.Context, id int) (*Hold, error)
+	// ReleaseHold releases an active hold and returns it.
+	ReleaseHold(ctx context.Context, id int) (*Hold, error)
+	// ConfirmHold creates the reservation of an active, unexpired hold and
+	// marks the hold confirmed.
+	ConfirmHold(ctx context.Context, hold *Hold, reservation *Reservation) error
+	// ExpireHolds expires the active holds that ran out and returns them.
+	ExpireHolds(ctx context.Context) ([]Hold, error)
+	// GetRoom returns the settings of a room, the defaults for rooms that
+	// were never configured.
+	GetRoom(ctx context.Context, roomID string) (*Room, error)
+	SaveRoom(ctx context.Context, room *Room) error
+	// ListResources returns the resources matching the query ordered by id.
+	// Rooms that were never configured are listed as rooms without
+	// attributes.
+	ListResources(ctx context.Context, query ResourceQuery) ([]Room, error)
+	// ListPending returns the pending reservations of a room, or of all
+	// rooms when roomID is empty, oldest request first.
+	ListPending(ctx context.Context, roomID string) ([]Reservation, error)
+	// SetStatus moves a pending reservation to approved or rejected.
+	SetStatus(ctx context.Context, reservation *Reservation, status string) error
+	// CheckIn records the check-in of a reservation that was not checked
+	// in yet.
+	CheckIn(ctx context.Context, reservation *Reservation) error
+	// ReleaseNoShows cancels the approved reservations that started before
+	// startedBefore, are not over and were not checked in, marking them as
+	// no-shows, and returns them.
+	ReleaseNoShows(ctx context.Context, startedBefore time.Time) ([]Reservation, error)
+	// CreateBatch stores the reservations in one transaction.
+	CreateBatch(ctx context.Context, reservations []Reservation) error
 }
@@ -0,0 +1,121 @@
+package models
+
+import "time"
+
+// Resource types. Desks, parking spots, equipment and vehicles are booked
+// like rooms: the room_id of their reservations is the resource id.
+const (
+	ResourceRoom      = "room"
+	ResourceDesk      = "desk"
+	ResourceParking   = "parking"
+	ResourceEquipment = "equipment"
+	ResourceVehicle   = "vehicle"
+)
+
+// ResourceTypes lists the known resource types.
+var ResourceTypes = []string{ResourceRoom, ResourceDesk, ResourceParking, ResourceEquipment, ResourceVehicle}
+
+// IsResourceType reports whether resourceType is a known resource type.
+func IsResourceType(resourceType string) bool {
+	for _, known := range ResourceTypes {
+		if known == resourceType {
+			return true
+		}
+	}
+	return false
+}
+
+// Room holds the per-room booking settings. Rooms are created implicitly by
+// booking them, so a room without stored settings uses DefaultRoom. Other
+// resources are rooms with another Type and have to be configured to be
+// found by type.
+//
+// A room can be a part of a combined room, like the halls a partition wall
+// splits a conference hall into. A reservation of the combined room blocks
+// all its parts and a reservation of a part blocks the combined room, while
+// parts do not block each other. Combined rooms are not nested.
+type Room struct {
+	ID   string `json:"room_id"`
+	Type string `json:"type"`
+	// Attributes describe the resource, like the floor of a desk or the
+	// seats of a car, and are what resources are searched by.
+	Attributes map[string]string `json:"attributes,omitempty"`
+	// ParentID is the combined room this room is a part of.
+	ParentID string `json:"parent_id,omitempty"`
+	// Parts are the rooms this room combines. They are set through the
+	// parts' ParentID.
+	Parts []string `json:"parts,omitempty"`
+	// RequiresApproval makes new reservations of the room pending until an
+	// approver decides on them.
+	RequiresApproval bool `json:"requires_approval"`
+	// PendingBlocks makes pending reservations keep their slot from other
+	// requests. Otherwise requests may overlap until one of them is
+	// approved, which rejects the others.
+	PendingBlocks bool `json:"pending_blocks"`
+	// Capacity turns the room into a shared space booked by the seat:
+	// reservations may overlap as long as the seats they take together
+	// never exceed it. Zero books the room as a whole.
+	Capacity  int       `json:"capacity"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// DefaultRoom returns the settings of a room that was never configured.
+func DefaultRoom(roomID string) Room {
+	return Room{
+		ID:            roomID,
+		Type:          ResourceRoom,
+		PendingBlocks: true,
+	}
+}
+
+// ResourceQuery selects resources of one type having all the attributes.
+// With an interval only resources free during all of it are selected.
+type ResourceQuery struct {
+	Type       string
+	Attributes map[string]string
+	StartTime  time.Time
+	EndTime    time.Time
+	Limit      int
+}
+
+// Group returns the room and the rooms whose reservations block it.
+func (r Room) Group() []string {
+	group := append([]string{r.ID}, r.Parts...)
+	if r.ParentID != "" {
+		group = append(group, r.ParentID)
+	}
+	return group
+}
+
+// Blocks reports whether reservations of roomID block the room.
+func (r Room) Blocks(roomID string) bool {
+	for _, id := range r.Group() {
+		if id == roomID {
+			return true
+		}
+	}
+	return false
+}
+
+// InitialStatus is the status of new reservations of the room.
+func (r Room) InitialStatus() string {
+	if r.RequiresApproval {
+		return StatusPending
+	}
+	return StatusApproved
+}
+
+// DeviceUserPrefix starts the user id of bookings made by a door display,
+// followed by the room id. Such users have no mailbox.
+const DeviceUserPrefix = "device:"
+
+// RoomStatus is what a door display shows: the reservation in progress,
+// the next one, and until when the room is free if it is.
+type RoomStatus struct {
+	RoomID    string       `json:"room_id"`
+	Occupied  bool         `json:"occupied"`
+	Current   *Reservation `json:"current"`
+	Next      *Reservation `json:"next"`
+	FreeUntil *time.Time   `json:"free_until"`
+	AsOf      time.Time    `json:"as_of"`
+}
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// Waitlist entry states. A waiting entry is offered the slot, or booked
+// straight away when AutoBook is set, once an overlapping reservation is
+// cancelled. Offers expire unless claimed in time, and the slot passes to
+// the next entry.
+const (
+	WaitlistWaiting   = "waiting"
+	WaitlistOffered   = "offered"
+	WaitlistBooked    = "booked"
+	WaitlistExpired   = "expired"
+	WaitlistWithdrawn = "withdrawn"
+)
+
+// WaitlistEntry is a user queueing for a booked interval of a room.
+type WaitlistEntry struct {
+	ID             int        `json:"id"`
+	RoomID         string     `json:"room_id"`
+	UserID         string     `json:"user_id"`
+	StartTime      time.Time  `json:"start_time"`
+	EndTime        time.Time  `json:"end_time"`
+	AutoBook       bool       `json:"auto_book"`
+	Status         string     `json:"status"`
+	OfferExpiresAt *time.Time `json:"offer_expires_at,omitempty"`
+	ReservationID  int        `json:"reservation_id,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// Reservation is the reservation the entry asks for.
+func (e WaitlistEntry) Reservation() Reservation {
+	return Reservation{
+		RoomID:    e.RoomID,
+		UserID:    e.UserID,
+		StartTime: e.StartTime,
+		EndTime:   e.EndTime,
+	}
+}
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// Webhook delivery states. Deliveries stay pending until the receiver
+// answers with a 2xx status or the attempts run out and they are dead.
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryDead      = "dead"
+)
+
+// Webhook is a subscription to reservation events. Empty Events or RoomIDs
+// match every event type or room. Secret signs the deliveries and is only
+// shown when the webhook is created.
+type Webhook struct {
+	ID        int       `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	RoomIDs   []string  `json:"room_ids"`
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// WebhookDelivery is one event queued for one webhook, together with the
+// outcome of its last attempt. URL and Secret are copied from the webhook
+// when the delivery is claimed.
+type WebhookDelivery struct {
+	ID             int64           `json:"id"`
+	WebhookID      int             `json:"webhook_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	LastError      string          `json:"last_error,omitempty"`
+	ResponseStatus int             `json:"response_status,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+
+	URL    string `json:"-"`
+	Secret string `json:"-"`
+}
+
+type WebhookService interface {
+	EventPublisher
+	CreateWebhook(ctx context.Context, webhook *Webhook) error
+	ListWebhooks(ctx context.Context) ([]Webhook, error)
+	DeleteWebhook(ctx context.Context, id int) error
+	ListDeliveries(ctx context.Context, webhookID int, limit int) ([]WebhookDelivery, error)
+	RetryDelivery(ctx context.Context, webhookID int, deliveryID int64) error
+}
+
+type WebhookStorage interface {
+	CreateWebhook(ctx context.Context, webhook *Webhook) error
+	ListWebhooks(ctx context.Context) ([]Webhook, error)
+	DeleteWebhook(ctx context.Context, id int) error
+	EnqueueDeliveries(ctx context.Context, event ReservationEvent, payload []byte) error
+	ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]WebhookDelivery, error)
+	CompleteDelivery(ctx context.Context, delivery *WebhookDelivery) error
+	ListDeliveries(ctx context.Context, webhookID int, limit int) ([]WebhookDelivery, error)
+	RetryDelivery(ctx context.Context, webhookID int, deliveryID int64) error
+}
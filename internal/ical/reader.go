@@ -0,0 +1,168 @@
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Property is a parsed content line.
+type Property struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+// Component is a parsed VEVENT with its properties keyed by name. Line is
+// the line number of its BEGIN:VEVENT, for error reporting.
+type Component struct {
+	Line       int
+	Properties map[string]Property
+}
+
+// Get returns the value of a property, or "" when it is missing.
+func (c Component) Get(name string) string {
+	return c.Properties[name].Value
+}
+
+// Time parses a DATE-TIME property. UTC, TZID-qualified and floating values
+// are supported, floating values are read in UTC.
+func (c Component) Time(name string) (time.Time, error) {
+	prop, ok := c.Properties[name]
+	if !ok {
+		return time.Time{}, fmt.Errorf("%s is missing", name)
+	}
+	if prop.Params["VALUE"] == "DATE" {
+		return time.Time{}, fmt.Errorf("%s: all-day values are not supported", name)
+	}
+
+	if strings.HasSuffix(prop.Value, "Z") {
+		return time.Parse(utcFormat, prop.Value)
+	}
+
+	loc := time.UTC
+	if tzid := prop.Params["TZID"]; tzid != "" {
+		var err error
+		loc, err = time.LoadLocation(tzid)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%s: unknown time zone %q", name, tzid)
+		}
+	}
+	return time.ParseInLocation(localFormat, prop.Value, loc)
+}
+
+// ReadEvents returns the VEVENT components of an iCalendar stream.
+// Nested components such as VALARM are skipped.
+func ReadEvents(r io.Reader) ([]Component, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []Component
+	var current *Component
+	depth := 0
+	for _, l := range lines {
+		prop, err := parseProperty(l.text)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", l.number, err)
+		}
+
+		switch {
+		case prop.Name == "BEGIN" && prop.Value == "VEVENT" && current == nil:
+			current = &Component{Line: l.number, Properties: map[string]Property{}}
+		case current == nil:
+		case prop.Name == "BEGIN":
+			depth++
+		case prop.Name == "END" && depth > 0:
+			depth--
+		case prop.Name == "END" && prop.Value == "VEVENT":
+			events = append(events, *current)
+			current = nil
+		case depth == 0:
+			if _, exists := current.Properties[prop.Name]; !exists {
+				current.Properties[prop.Name] = prop
+			}
+		}
+	}
+
+	if current != nil {
+		return nil, fmt.Errorf("line %d: VEVENT is not terminated", current.Line)
+	}
+
+	return events, nil
+}
+
+// Unescape reverses Escape for TEXT values.
+func Unescape(value string) string {
+	return strings.NewReplacer(
+		`\\`, `\`,
+		`\;`, ";",
+		`\,`, ",",
+		`\n`, "\n",
+		`\N`, "\n",
+	).Replace(value)
+}
+
+type contentLine struct {
+	number int
+	text   string
+}
+
+func unfold(r io.Reader) ([]contentLine, error) {
+	var lines []contentLine
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	number := 0
+	for scanner.Scan() {
+		number++
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(text, " ") || strings.HasPrefix(text, "\t")) {
+			lines[len(lines)-1].text += text[1:]
+			continue
+		}
+		if text == "" {
+			continue
+		}
+		lines = append(lines, contentLine{number: number, text: text})
+	}
+
+	return lines, scanner.Err()
+}
+
+func parseProperty(line string) (Property, error) {
+	colon := valueSeparator(line)
+	if colon < 0 {
+		return Property{}, fmt.Errorf("malformed content line %q", line)
+	}
+
+	parts := strings.Split(line[:colon], ";")
+	prop := Property{
+		Name:   strings.ToUpper(parts[0]),
+		Params: map[string]string{},
+		Value:  line[colon+1:],
+	}
+	for _, param := range parts[1:] {
+		name, value, _ := strings.Cut(param, "=")
+		prop.Params[strings.ToUpper(name)] = strings.Trim(value, `"`)
+	}
+
+	return prop, nil
+}
+
+// valueSeparator finds the colon that starts the value, ignoring colons in
+// quoted parameter values.
+func valueSeparator(line string) int {
+	quoted := false
+	for i, c := range line {
+		switch {
+		case c == '"':
+			quoted = !quoted
+		case c == ':' && !quoted:
+			return i
+		}
+	}
+	return -1
+}
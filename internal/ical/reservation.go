@@ -0,0 +1,44 @@
+package ical
+
+import (
+	"fmt"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+)
+
+const reservationUIDFormat = "reservation-%d@meeting-room-booking"
+
+// ReservationEvent maps a reservation to a VEVENT with a UID that stays the
+// same for the lifetime of the reservation.
+func ReservationEvent(reservation models.Reservation) Event {
+	return Event{
+		UID:       ReservationUID(reservation),
+		Summary:   "Room " + reservation.RoomID + " booking",
+		Location:  reservation.RoomID,
+		Start:     reservation.StartTime,
+		End:       reservation.EndTime,
+		Stamp:     reservation.UpdatedAt,
+		Sequence:  reservation.Version - 1,
+		Cancelled: reservation.CancelledAt != nil,
+		Tentative: reservation.Status == models.StatusPending,
+	}
+}
+
+// ReservationUID is the UID chosen by the CalDAV client that created the
+// reservation or, for reservations made through the API, one derived from
+// its ID.
+func ReservationUID(reservation models.Reservation) string {
+	if reservation.ICalUID != "" {
+		return reservation.ICalUID
+	}
+	return fmt.Sprintf(reservationUIDFormat, reservation.ID)
+}
+
+// ParseReservationUID returns the ID encoded in a UID made by ReservationUID.
+func ParseReservationUID(uid string) (int, bool) {
+	var id int
+	if _, err := fmt.Sscanf(uid, reservationUIDFormat, &id); err != nil || fmt.Sprintf(reservationUIDFormat, id) != uid {
+		return 0, false
+	}
+	return id, true
+}
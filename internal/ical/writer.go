@@ -0,0 +1,273 @@
+// Package ical writes the subset of RFC 5545 iCalendar used to publish
+// reservations to calendar clients.
+package ical
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	prodID      = "-//Seven11Eleven//Meeting Room Booking//EN"
+	utcFormat   = "20060102T150405Z"
+	localFormat = "20060102T150405"
+
+	// maxLineOctets is the RFC 5545 content line limit before folding.
+	maxLineOctets = 75
+)
+
+// Event is a single VEVENT. Sequence must grow whenever the event changes so
+// clients replace their cached copy.
+type Event struct {
+	UID         string
+	Summary     string
+	Location    string
+	Description string
+	Start       time.Time
+	End         time.Time
+	Stamp       time.Time
+	Sequence    int
+	Cancelled   bool
+	// Tentative marks events that are not confirmed yet.
+	Tentative bool
+}
+
+// Period is a busy interval of a VFREEBUSY.
+type Period struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Writer streams a VCALENDAR object. Errors are sticky and reported by End.
+type Writer struct {
+	w   io.Writer
+	loc *time.Location
+	err error
+}
+
+// NewWriter returns a writer rendering event times in loc. A nil or UTC
+// location renders times in UTC without a VTIMEZONE.
+func NewWriter(w io.Writer, loc *time.Location) *Writer {
+	if loc == nil {
+		loc = time.UTC
+	}
+	return &Writer{w: w, loc: loc}
+}
+
+// Begin writes the calendar header. from and to bound the period for which
+// time zone transitions are published.
+func (w *Writer) Begin(name string, from, to time.Time) {
+	w.header(true)
+	if name != "" {
+		w.line("X-WR-CALNAME", Escape(name))
+	}
+	w.line("X-WR-TIMEZONE", w.loc.String())
+	if w.loc != time.UTC {
+		w.timezone(from, to)
+	}
+}
+
+// BeginObject writes the header of a CalDAV calendar object resource, which
+// unlike a published calendar must not carry a METHOD.
+func (w *Writer) BeginObject(from, to time.Time) {
+	w.header(false)
+	if w.loc != time.UTC {
+		w.timezone(from, to)
+	}
+}
+
+func (w *Writer) header(publish bool) {
+	w.line("BEGIN", "VCALENDAR")
+	w.line("VERSION", "2.0")
+	w.line("PRODID", prodID)
+	w.line("CALSCALE", "GREGORIAN")
+	if publish {
+		w.line("METHOD", "PUBLISH")
+	}
+}
+
+// WriteEvent writes one VEVENT.
+func (w *Writer) WriteEvent(event Event) {
+	w.line("BEGIN", "VEVENT")
+	w.line("UID", Escape(event.UID))
+	w.line("DTSTAMP", event.Stamp.UTC().Format(utcFormat))
+	w.time("DTSTART", event.Start)
+	w.time("DTEND", event.End)
+	w.line("SEQUENCE", fmt.Sprint(event.Sequence))
+	if event.Summary != "" {
+		w.line("SUMMARY", Escape(event.Summary))
+	}
+	if event.Location != "" {
+		w.line("LOCATION", Escape(event.Location))
+	}
+	if event.Description != "" {
+		w.line("DESCRIPTION", Escape(event.Description))
+	}
+	switch {
+	case event.Cancelled:
+		w.line("STATUS", "CANCELLED")
+	case event.Tentative:
+		w.line("STATUS", "TENTATIVE")
+	default:
+		w.line("STATUS", "CONFIRMED")
+	}
+	w.line("END", "VEVENT")
+}
+
+// WriteFreeBusy writes one VFREEBUSY covering [start, end) with the given
+// busy periods. A non-empty roomID is written as X-ROOM-ID. Free-busy times
+// are always written in UTC.
+func (w *Writer) WriteFreeBusy(roomID string, stamp, start, end time.Time, busy []Period) {
+	w.line("BEGIN", "VFREEBUSY")
+	w.line("DTSTAMP", FormatUTC(stamp))
+	w.line("DTSTART", FormatUTC(start))
+	w.line("DTEND", FormatUTC(end))
+	if roomID != "" {
+		w.line("X-ROOM-ID", Escape(roomID))
+	}
+	for _, period := range busy {
+		w.line("FREEBUSY;FBTYPE=BUSY", FormatUTC(period.Start)+"/"+FormatUTC(period.End))
+	}
+	w.line("END", "VFREEBUSY")
+}
+
+// WriteRaw writes an already formatted property, for components the writer
+// has no dedicated method for.
+func (w *Writer) WriteRaw(name, value string) {
+	w.line(name, value)
+}
+
+// End closes the calendar and returns the first write error, if any.
+func (w *Writer) End() error {
+	w.line("END", "VCALENDAR")
+	return w.err
+}
+
+// FormatUTC formats t as an iCalendar UTC date-time.
+func FormatUTC(t time.Time) string {
+	return t.UTC().Format(utcFormat)
+}
+
+// ParseUTC parses an iCalendar UTC date-time.
+func ParseUTC(value string) (time.Time, error) {
+	return time.Parse(utcFormat, value)
+}
+
+// Escape escapes a TEXT property value.
+func Escape(value string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(value)
+}
+
+func (w *Writer) time(name string, t time.Time) {
+	if w.loc == time.UTC {
+		w.line(name, t.UTC().Format(utcFormat))
+		return
+	}
+	w.line(name+";TZID="+w.loc.String(), t.In(w.loc).Format(localFormat))
+}
+
+// timezone writes a VTIMEZONE listing every UTC offset change of the writer's
+// location between from and to as explicit observances.
+func (w *Writer) timezone(from, to time.Time) {
+	w.line("BEGIN", "VTIMEZONE")
+	w.line("TZID", w.loc.String())
+
+	current := from.In(w.loc)
+	w.observance(current, current)
+	for _, transition := range transitions(w.loc, from, to) {
+		w.observance(current, transition)
+		current = transition
+	}
+
+	w.line("END", "VTIMEZONE")
+}
+
+func (w *Writer) observance(before, onset time.Time) {
+	component := "STANDARD"
+	if onset.IsDST() {
+		component = "DAYLIGHT"
+	}
+	name, offset := onset.Zone()
+	_, previous := before.Zone()
+
+	w.line("BEGIN", component)
+	w.line("DTSTART", onset.In(time.FixedZone(name, previous)).Format(localFormat))
+	w.line("TZOFFSETFROM", formatOffset(previous))
+	w.line("TZOFFSETTO", formatOffset(offset))
+	w.line("TZNAME", Escape(name))
+	w.line("END", component)
+}
+
+// transitions returns the instants in (from, to] at which loc changes its
+// UTC offset.
+func transitions(loc *time.Location, from, to time.Time) []time.Time {
+	var result []time.Time
+	prev := from.In(loc)
+	for day := prev.Add(24 * time.Hour); !prev.After(to); day = day.Add(24 * time.Hour) {
+		next := day.In(loc)
+		if offsetOf(prev) != offsetOf(next) {
+			lo, hi := prev, next
+			for hi.Sub(lo) > time.Second {
+				mid := lo.Add(hi.Sub(lo) / 2)
+				if offsetOf(mid) == offsetOf(lo) {
+					lo = mid
+				} else {
+					hi = mid
+				}
+			}
+			// offsets change on whole seconds, the only one in (lo, lo+1s]
+			result = append(result, lo.Truncate(time.Second).Add(time.Second))
+		}
+		prev = next
+	}
+	return result
+}
+
+func offsetOf(t time.Time) int {
+	_, offset := t.Zone()
+	return offset
+}
+
+func formatOffset(seconds int) string {
+	sign := '+'
+	if seconds < 0 {
+		sign = '-'
+		seconds = -seconds
+	}
+	return fmt.Sprintf("%c%02d%02d", sign, seconds/3600, seconds%3600/60)
+}
+
+// line writes a content line, folding it at 75 octets without splitting
+// UTF-8 sequences.
+func (w *Writer) line(name, value string) {
+	if w.err != nil {
+		return
+	}
+
+	content := name + ":" + value
+	var b strings.Builder
+	limit := maxLineOctets
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+		b.WriteString(content[:cut])
+		b.WriteString("\r\n ")
+		content = content[cut:]
+		limit = maxLineOctets - 1
+	}
+	b.WriteString(content)
+	b.WriteString("\r\n")
+
+	_, w.err = io.WriteString(w.w, b.String())
+}
@@ -0,0 +1,102 @@
+package ical_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/ical"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriter(t *testing.T) {
+	start := time.Date(2030, 9, 1, 10, 0, 0, 0, time.UTC)
+	event := ical.Event{
+		UID:      "reservation-1@meeting-room-booking",
+		Summary:  "Planning; budget, Q4",
+		Start:    start,
+		End:      start.Add(time.Hour),
+		Stamp:    start.Add(-time.Hour),
+		Sequence: 2,
+	}
+
+	t.Run("utc calendar", func(t *testing.T) {
+		var b strings.Builder
+		w := ical.NewWriter(&b, time.UTC)
+		w.Begin("Room 411", start, start)
+		w.WriteEvent(event)
+		require.NoError(t, w.End())
+
+		out := b.String()
+		assert.True(t, strings.HasPrefix(out, "BEGIN:VCALENDAR\r\n"))
+		assert.True(t, strings.HasSuffix(out, "END:VCALENDAR\r\n"))
+		assert.Contains(t, out, "DTSTART:20300901T100000Z\r\n")
+		assert.Contains(t, out, `SUMMARY:Planning\; budget\, Q4`+"\r\n")
+		assert.Contains(t, out, "SEQUENCE:2\r\n")
+		assert.Contains(t, out, "STATUS:CONFIRMED\r\n")
+		assert.NotContains(t, out, "VTIMEZONE")
+	})
+
+	t.Run("cancelled event", func(t *testing.T) {
+		cancelled := event
+		cancelled.Cancelled = true
+
+		var b strings.Builder
+		w := ical.NewWriter(&b, nil)
+		w.Begin("", start, start)
+		w.WriteEvent(cancelled)
+		require.NoError(t, w.End())
+
+		assert.Contains(t, b.String(), "STATUS:CANCELLED\r\n")
+	})
+
+	t.Run("tentative event", func(t *testing.T) {
+		tentative := event
+		tentative.Tentative = true
+
+		var b strings.Builder
+		w := ical.NewWriter(&b, nil)
+		w.Begin("", start, start)
+		w.WriteEvent(tentative)
+		require.NoError(t, w.End())
+
+		assert.Contains(t, b.String(), "STATUS:TENTATIVE\r\n")
+	})
+
+	t.Run("long lines are folded", func(t *testing.T) {
+		long := event
+		long.Description = strings.Repeat("переговорная ", 20)
+
+		var b strings.Builder
+		w := ical.NewWriter(&b, time.UTC)
+		w.Begin("", start, start)
+		w.WriteEvent(long)
+		require.NoError(t, w.End())
+
+		for _, line := range strings.Split(b.String(), "\r\n") {
+			assert.LessOrEqual(t, len(line), 75)
+		}
+		unfolded := strings.ReplaceAll(b.String(), "\r\n ", "")
+		assert.Contains(t, unfolded, "DESCRIPTION:"+long.Description)
+	})
+
+	t.Run("local time zone with transitions", func(t *testing.T) {
+		loc, err := time.LoadLocation("Europe/Berlin")
+		if err != nil {
+			t.Skip("time zone database is not available")
+		}
+
+		var b strings.Builder
+		w := ical.NewWriter(&b, loc)
+		w.Begin("", time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2030, 12, 31, 0, 0, 0, 0, time.UTC))
+		w.WriteEvent(event)
+		require.NoError(t, w.End())
+
+		out := b.String()
+		assert.Contains(t, out, "TZID:Europe/Berlin\r\n")
+		assert.Contains(t, out, "BEGIN:DAYLIGHT\r\nDTSTART:20300331T020000\r\nTZOFFSETFROM:+0100\r\nTZOFFSETTO:+0200\r\n")
+		assert.Contains(t, out, "BEGIN:STANDARD\r\nDTSTART:20301027T030000\r\nTZOFFSETFROM:+0200\r\nTZOFFSETTO:+0100\r\n")
+		assert.Contains(t, out, "DTSTART;TZID=Europe/Berlin:20300901T120000\r\n")
+	})
+}
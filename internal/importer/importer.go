@@ -0,0 +1,174 @@
+// Package importer turns CSV and iCalendar files into reservation rows for
+// bulk import.
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/ical"
+)
+
+const (
+	FormatCSV = "csv"
+	FormatICS = "ics"
+)
+
+// FormatFromContentType maps a request Content-Type to an import format.
+func FormatFromContentType(contentType string) string {
+	switch {
+	case strings.HasPrefix(contentType, "text/csv"):
+		return FormatCSV
+	case strings.HasPrefix(contentType, "text/calendar"):
+		return FormatICS
+	default:
+		return ""
+	}
+}
+
+// Parse reads rows in the given format.
+func Parse(r io.Reader, format string) ([]models.ImportRow, error) {
+	switch format {
+	case FormatCSV:
+		return ParseCSV(r)
+	case FormatICS:
+		return ParseICS(r)
+	default:
+		return nil, models.ErrUnsupportedFormat
+	}
+}
+
+// ParseCSV reads a CSV file with a header row naming at least the room_id,
+// start_time and end_time columns, and optionally user_id. Times are RFC 3339.
+func ParseCSV(r io.Reader) ([]models.ImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: cannot read csv header: %v", models.ErrInvalidImportRow, err)
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"room_id", "start_time", "end_time"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("%w: csv header has no %s column", models.ErrInvalidImportRow, required)
+		}
+	}
+
+	var rows []models.ImportRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		line, _ := reader.FieldPos(0)
+		if err != nil {
+			rows = append(rows, models.ImportRow{Line: line, Err: rowError(err.Error())})
+			continue
+		}
+
+		field := func(name string) string {
+			i, ok := columns[name]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		row := models.ImportRow{
+			Line: line,
+			Reservation: models.Reservation{
+				RoomID: field("room_id"),
+				UserID: field("user_id"),
+			},
+		}
+		row.Reservation.StartTime, row.Err = parseTime("start_time", field("start_time"))
+		if row.Err == nil {
+			row.Reservation.EndTime, row.Err = parseTime("end_time", field("end_time"))
+		}
+		if row.Err == nil && row.Reservation.RoomID == "" {
+			row.Err = rowError("room_id is empty")
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// ParseICS reads the VEVENTs of an iCalendar file. The room is taken from
+// X-ROOM-ID or LOCATION and the user from X-USER-ID or the ORGANIZER address.
+// Cancelled events are skipped.
+func ParseICS(r io.Reader) ([]models.ImportRow, error) {
+	events, err := ical.ReadEvents(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", models.ErrInvalidImportRow, err)
+	}
+
+	var rows []models.ImportRow
+	for _, event := range events {
+		if strings.EqualFold(event.Get("STATUS"), "CANCELLED") {
+			continue
+		}
+
+		row := models.ImportRow{Line: event.Line}
+
+		row.Reservation.RoomID = ical.Unescape(event.Get("X-ROOM-ID"))
+		if row.Reservation.RoomID == "" {
+			row.Reservation.RoomID = ical.Unescape(event.Get("LOCATION"))
+		}
+		row.Reservation.UserID = EventUser(event)
+
+		row.Reservation.StartTime, row.Err = event.Time("DTSTART")
+		if row.Err == nil {
+			row.Reservation.EndTime, row.Err = event.Time("DTEND")
+		}
+		if row.Err != nil {
+			row.Err = rowError(row.Err.Error())
+		} else if row.Reservation.RoomID == "" {
+			row.Err = rowError("event has neither X-ROOM-ID nor LOCATION")
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// EventUser returns the booking user of an event from X-USER-ID or the
+// ORGANIZER address, or "" when neither is set.
+func EventUser(event ical.Component) string {
+	if user := ical.Unescape(event.Get("X-USER-ID")); user != "" {
+		return user
+	}
+	organizer := event.Get("ORGANIZER")
+	if len(organizer) > len("mailto:") && strings.EqualFold(organizer[:len("mailto:")], "mailto:") {
+		return organizer[len("mailto:"):]
+	}
+	return ""
+}
+
+func parseTime(field, value string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, &models.Error{
+			Code:    models.ErrInvalidImportRow.Code,
+			Field:   field,
+			Message: fmt.Sprintf("%s must be an RFC 3339 timestamp", field),
+		}
+	}
+	return t, nil
+}
+
+func rowError(message string) error {
+	return &models.Error{Code: models.ErrInvalidImportRow.Code, Message: message}
+}
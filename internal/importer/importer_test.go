@@ -0,0 +1,75 @@
+package importer_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/importer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCSV(t *testing.T) {
+	input := "room_id,start_time,end_time,user_id\n" +
+		"411,2030-09-01T10:00:00Z,2030-09-01T11:00:00Z,alice\n" +
+		"412,yesterday,2030-09-01T11:00:00Z,bob\n"
+
+	rows, err := importer.ParseCSV(strings.NewReader(input))
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+
+	assert.NoError(t, rows[0].Err)
+	assert.Equal(t, 2, rows[0].Line)
+	assert.Equal(t, "411", rows[0].Reservation.RoomID)
+	assert.Equal(t, "alice", rows[0].Reservation.UserID)
+	assert.Equal(t, time.Date(2030, 9, 1, 10, 0, 0, 0, time.UTC), rows[0].Reservation.StartTime)
+
+	assert.ErrorIs(t, rows[1].Err, models.ErrInvalidImportRow)
+	assert.Equal(t, 3, rows[1].Line)
+}
+
+func TestParseCSVMissingColumn(t *testing.T) {
+	_, err := importer.ParseCSV(strings.NewReader("room_id,start_time\n411,2030-09-01T10:00:00Z\n"))
+	assert.ErrorIs(t, err, models.ErrInvalidImportRow)
+}
+
+func TestParseICS(t *testing.T) {
+	input := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"BEGIN:VEVENT",
+		"UID:1@legacy",
+		"DTSTART;TZID=Europe/Berlin:20300901T120000",
+		"DTEND:20300901T110000Z",
+		"LOCATION:Room 4",
+		" 11",
+		"ORGANIZER;CN=\"Alice: Ops\":mailto:alice@example.com",
+		"BEGIN:VALARM",
+		"TRIGGER:-PT15M",
+		"END:VALARM",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:2@legacy",
+		"STATUS:CANCELLED",
+		"DTSTART:20300902T100000Z",
+		"DTEND:20300902T110000Z",
+		"LOCATION:411",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	rows, err := importer.ParseICS(strings.NewReader(input))
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+
+	row := rows[0]
+	require.NoError(t, row.Err)
+	assert.Equal(t, "Room 411", row.Reservation.RoomID)
+	assert.Equal(t, "alice@example.com", row.Reservation.UserID)
+	assert.True(t, row.Reservation.EndTime.Equal(time.Date(2030, 9, 1, 11, 0, 0, 0, time.UTC)))
+	if _, err := time.LoadLocation("Europe/Berlin"); err == nil {
+		assert.True(t, row.Reservation.StartTime.Equal(time.Date(2030, 9, 1, 10, 0, 0, 0, time.UTC)))
+	}
+}
@@ -0,0 +1,166 @@
+package notify_test
+
+import (
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models/mocks"
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/notify"
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/notify/smtptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newNotification(kind string) models.Notification {
+	start := time.Date(2030, 9, 1, 10, 0, 0, 0, time.UTC)
+	return models.Notification{
+		ID:        1,
+		Kind:      kind,
+		Recipient: "alice@example.com",
+		Reservation: models.Reservation{
+			ID:        7,
+			RoomID:    "411",
+			UserID:    "alice@example.com",
+			StartTime: start,
+			EndTime:   start.Add(time.Hour),
+			Version:   1,
+		},
+	}
+}
+
+func TestRenderer(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Moscow")
+	require.NoError(t, err)
+	renderer, err := notify.NewRenderer("", loc)
+	require.NoError(t, err)
+
+	t.Run("event with calendar attachment", func(t *testing.T) {
+		mail, err := renderer.Render(newNotification(models.EventReservationCreated))
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"alice@example.com"}, mail.To)
+		assert.Equal(t, "Room 411 booked for Sun, 01 Sep 2030 13:00", mail.Subject)
+		assert.Contains(t, mail.Body, "Booking: #7")
+		require.Len(t, mail.Attachments, 1)
+		assert.Contains(t, string(mail.Attachments[0].Data), "UID:reservation-7@meeting-room-booking\r\n")
+	})
+
+	t.Run("pending request", func(t *testing.T) {
+		notification := newNotification(models.EventReservationCreated)
+		notification.Reservation.Status = models.StatusPending
+
+		mail, err := renderer.Render(notification)
+		require.NoError(t, err)
+
+		assert.Equal(t, "Room 411 requested for Sun, 01 Sep 2030 13:00", mail.Subject)
+		assert.Contains(t, mail.Body, "awaits approval")
+		require.Len(t, mail.Attachments, 1)
+		assert.Contains(t, string(mail.Attachments[0].Data), "STATUS:TENTATIVE\r\n")
+	})
+
+	t.Run("waitlist offer", func(t *testing.T) {
+		notification := newNotification(models.EventWaitlistOffered)
+		expires := time.Date(2030, 8, 30, 9, 15, 0, 0, time.UTC)
+		notification.Waitlist = &models.WaitlistEntry{ID: 3, Status: models.WaitlistOffered, OfferExpiresAt: &expires}
+
+		mail, err := renderer.Render(notification)
+		require.NoError(t, err)
+
+		assert.Contains(t, mail.Body, "until Fri, 30 Aug 2030 12:15")
+		assert.Contains(t, mail.Body, "POST /waitlist/3/claim")
+		assert.Empty(t, mail.Attachments)
+	})
+
+	t.Run("reminder", func(t *testing.T) {
+		mail, err := renderer.Render(newNotification(models.NotificationReminder))
+		require.NoError(t, err)
+
+		assert.Equal(t, "Reminder: room 411 at 13:00", mail.Subject)
+		assert.Empty(t, mail.Attachments)
+	})
+}
+
+func TestSMTPMailer(t *testing.T) {
+	server, err := smtptest.NewServer()
+	require.NoError(t, err)
+	defer server.Close()
+
+	mailer := notify.NewSMTPMailer(server.Addr, "rooms@example.com", "", "")
+	err = mailer.Send(context.Background(), models.Mail{
+		To:      []string{"alice@example.com"},
+		Subject: "Бронь подтверждена",
+		Body:    "Room 411\n",
+		Attachments: []models.MailAttachment{
+			{Filename: "reservation.ics", ContentType: "text/calendar; charset=utf-8", Data: []byte("BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n")},
+		},
+	})
+	require.NoError(t, err)
+
+	messages := server.Messages()
+	require.Len(t, messages, 1)
+	assert.Equal(t, "rooms@example.com", messages[0].From)
+	assert.Equal(t, []string{"alice@example.com"}, messages[0].To)
+
+	message, err := mail.ReadMessage(strings.NewReader(messages[0].Data))
+	require.NoError(t, err)
+	subject, err := new(mime.WordDecoder).DecodeHeader(message.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, "Бронь подтверждена", subject)
+
+	_, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	reader := multipart.NewReader(message.Body, params["boundary"])
+
+	body, err := reader.NextPart()
+	require.NoError(t, err)
+	text, err := io.ReadAll(body)
+	require.NoError(t, err)
+	assert.Equal(t, "Room 411\r\n", string(text))
+
+	attachment, err := reader.NextPart()
+	require.NoError(t, err)
+	assert.Equal(t, "reservation.ics", attachment.FileName())
+}
+
+func TestWorker(t *testing.T) {
+	server, err := smtptest.NewServer()
+	require.NoError(t, err)
+	defer server.Close()
+
+	renderer, err := notify.NewRenderer("", time.UTC)
+	require.NoError(t, err)
+
+	var completed []models.Notification
+	storage := mocks.NewNotificationStorage(t)
+	storage.On("ClaimNotifications", mock.Anything, 20, mock.Anything).
+		Return([]models.Notification{newNotification(models.EventReservationCancelled)}, nil)
+	storage.On("CompleteNotification", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		completed = append(completed, *args.Get(1).(*models.Notification))
+	}).Return(nil)
+
+	worker := notify.NewWorker(storage, notify.NewSMTPMailer(server.Addr, "rooms@example.com", "", ""), renderer)
+
+	server.Reject(true)
+	require.NoError(t, worker.RunOnce(context.Background()))
+	require.Len(t, completed, 1)
+	assert.Equal(t, models.DeliveryPending, completed[0].Status)
+	assert.Equal(t, 1, completed[0].Attempts)
+	assert.True(t, completed[0].SendAt.After(time.Now()))
+	assert.Contains(t, completed[0].LastError, "451")
+
+	server.Reject(false)
+	require.NoError(t, worker.RunOnce(context.Background()))
+	require.Len(t, completed, 2)
+	assert.Equal(t, models.DeliveryDelivered, completed[1].Status)
+	assert.NotNil(t, completed[1].SentAt)
+	require.Len(t, server.Messages(), 1)
+	assert.Contains(t, server.Messages()[0].Data, "Subject: Room 411 booking for Sun, 01 Sep 2030 10:00 cancelled")
+}
@@ -0,0 +1,124 @@
+// Package notify renders reservation notifications and sends them by email.
+package notify
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/ical"
+)
+
+//go:embed templates/*.tmpl
+var templateFiles embed.FS
+
+// defaultTemplates define "<kind>.subject" and "<kind>.body" for every
+// notification kind.
+var defaultTemplates = template.Must(template.ParseFS(templateFiles, "templates/*.tmpl"))
+
+const timeLayout = "Mon, 02 Jan 2006 15:04"
+
+// Renderer turns notifications into emails with the reservation attached as
+// an iCalendar file.
+type Renderer struct {
+	Templates *template.Template
+	Location  *time.Location
+}
+
+// NewRenderer returns a renderer using the built-in templates, or the
+// templates matching pattern when it is not empty.
+func NewRenderer(pattern string, loc *time.Location) (*Renderer, error) {
+	templates := defaultTemplates
+	if pattern != "" {
+		var err error
+		templates, err = template.ParseGlob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse mail templates: %w", err)
+		}
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	return &Renderer{
+		Templates: templates,
+		Location:  loc,
+	}, nil
+}
+
+// templateData is what the templates see: the reservation plus its times
+// formatted in the renderer's location.
+type templateData struct {
+	models.Reservation
+	Start     string
+	End       string
+	StartTime string
+	Zone      string
+
+	// Waitlist and ClaimBy are set for waitlist offers.
+	Waitlist *models.WaitlistEntry
+	ClaimBy  string
+}
+
+// Render builds the email for a notification.
+func (r *Renderer) Render(notification models.Notification) (models.Mail, error) {
+	reservation := notification.Reservation
+	start := reservation.StartTime.In(r.Location)
+	data := templateData{
+		Reservation: reservation,
+		Start:       start.Format(timeLayout),
+		End:         reservation.EndTime.In(r.Location).Format(timeLayout),
+		StartTime:   start.Format("15:04"),
+		Zone:        r.Location.String(),
+		Waitlist:    notification.Waitlist,
+	}
+	if notification.Waitlist != nil && notification.Waitlist.OfferExpiresAt != nil {
+		data.ClaimBy = notification.Waitlist.OfferExpiresAt.In(r.Location).Format(timeLayout)
+	}
+
+	subject, err := r.execute(notification.Kind+".subject", data)
+	if err != nil {
+		return models.Mail{}, err
+	}
+	body, err := r.execute(notification.Kind+".body", data)
+	if err != nil {
+		return models.Mail{}, err
+	}
+
+	mail := models.Mail{
+		To:      []string{notification.Recipient},
+		Subject: strings.TrimSpace(subject),
+		Body:    body,
+	}
+
+	// only actual reservations go to the calendar
+	if strings.HasPrefix(notification.Kind, "reservation.") && notification.Kind != models.NotificationReminder {
+		var calendar bytes.Buffer
+		writer := ical.NewWriter(&calendar, r.Location)
+		writer.Begin("", reservation.StartTime, reservation.EndTime)
+		writer.WriteEvent(ical.ReservationEvent(reservation))
+		if err := writer.End(); err != nil {
+			return models.Mail{}, err
+		}
+
+		mail.Attachments = append(mail.Attachments, models.MailAttachment{
+			Filename:    "reservation.ics",
+			ContentType: "text/calendar; charset=utf-8; method=PUBLISH",
+			Data:        calendar.Bytes(),
+		})
+	}
+
+	return mail, nil
+}
+
+func (r *Renderer) execute(name string, data templateData) (string, error) {
+	var out strings.Builder
+	if err := r.Templates.ExecuteTemplate(&out, name, data); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
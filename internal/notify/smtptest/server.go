@@ -0,0 +1,163 @@
+// Package smtptest provides an in-memory SMTP server that captures the
+// messages sent to it, for tests of code that sends email.
+package smtptest
+
+import (
+	"bufio"
+	"net"
+	"net/textproto"
+	"strings"
+	"sync"
+)
+
+// Message is one message accepted by the server.
+type Message struct {
+	From string
+	To   []string
+	Data string
+}
+
+// Server speaks just enough SMTP for net/smtp: no TLS, no authentication.
+type Server struct {
+	Addr string
+
+	listener net.Listener
+	wg       sync.WaitGroup
+
+	mu       sync.Mutex
+	messages []Message
+	reject   bool
+}
+
+// NewServer starts a server on a random local port.
+func NewServer() (*Server, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		Addr:     listener.Addr().String(),
+		listener: listener,
+	}
+	s.wg.Add(1)
+	go s.serve()
+
+	return s, nil
+}
+
+// Messages returns the messages accepted so far.
+func (s *Server) Messages() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Message(nil), s.messages...)
+}
+
+// Reject makes the server refuse new messages with a temporary failure.
+func (s *Server) Reject(reject bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.reject = reject
+}
+
+// Close stops the server and waits for open sessions to end.
+func (s *Server) Close() {
+	s.listener.Close()
+	s.wg.Wait()
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.session(conn)
+		}()
+	}
+}
+
+func (s *Server) session(conn net.Conn) {
+	defer conn.Close()
+
+	text := textproto.NewConn(conn)
+	text.PrintfLine("220 smtptest ready")
+
+	var message Message
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+
+		switch strings.ToUpper(verb) {
+		case "EHLO", "HELO":
+			text.PrintfLine("250 smtptest")
+		case "MAIL":
+			s.mu.Lock()
+			reject := s.reject
+			s.mu.Unlock()
+			if reject {
+				text.PrintfLine("451 try again later")
+				continue
+			}
+			message = Message{From: address(arg)}
+			text.PrintfLine("250 OK")
+		case "RCPT":
+			message.To = append(message.To, address(arg))
+			text.PrintfLine("250 OK")
+		case "DATA":
+			text.PrintfLine("354 end data with <CR><LF>.<CR><LF>")
+			data, err := readData(text.R)
+			if err != nil {
+				return
+			}
+			message.Data = data
+			s.mu.Lock()
+			s.messages = append(s.messages, message)
+			s.mu.Unlock()
+			text.PrintfLine("250 OK")
+		case "RSET", "NOOP":
+			text.PrintfLine("250 OK")
+		case "QUIT":
+			text.PrintfLine("221 bye")
+			return
+		default:
+			text.PrintfLine("502 command not implemented")
+		}
+	}
+}
+
+// address strips "FROM:<...>" and "TO:<...>" down to the address.
+func address(arg string) string {
+	_, value, _ := strings.Cut(arg, ":")
+	value = strings.TrimSpace(value)
+	if i := strings.IndexByte(value, ' '); i >= 0 {
+		value = value[:i]
+	}
+	return strings.Trim(value, "<>")
+}
+
+func readData(r *bufio.Reader) (string, error) {
+	var data strings.Builder
+	reader := textproto.NewReader(r)
+	for {
+		line, err := reader.ReadLine()
+		if err != nil {
+			return "", err
+		}
+		if line == "." {
+			return data.String(), nil
+		}
+		data.WriteString(strings.TrimPrefix(line, "."))
+		data.WriteString("\r\n")
+	}
+}
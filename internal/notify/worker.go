@@ -0,0 +1,117 @@
+package notify
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+)
+
+// Worker sends queued notifications once they are due. Failed attempts are
+// retried with exponential backoff until MaxAttempts is reached, after which
+// the notification is dead.
+type Worker struct {
+	Storage      models.NotificationStorage
+	Mailer       models.Mailer
+	Renderer     *Renderer
+	PollInterval time.Duration
+	BatchSize    int
+	MaxAttempts  int
+	BaseBackoff  time.Duration
+	MaxBackoff   time.Duration
+}
+
+func NewWorker(storage models.NotificationStorage, mailer models.Mailer, renderer *Renderer) *Worker {
+	return &Worker{
+		Storage:      storage,
+		Mailer:       mailer,
+		Renderer:     renderer,
+		PollInterval: 5 * time.Second,
+		BatchSize:    20,
+		MaxAttempts:  6,
+		BaseBackoff:  time.Minute,
+		MaxBackoff:   time.Hour,
+	}
+}
+
+// Run sends notifications until ctx is cancelled.
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.RunOnce(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Notification delivery failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce claims one batch of due notifications and attempts each of them.
+func (w *Worker) RunOnce(ctx context.Context) error {
+	lease := time.Duration(w.BatchSize+1) * sendTimeout
+	notifications, err := w.Storage.ClaimNotifications(ctx, w.BatchSize, lease)
+	if err != nil {
+		return err
+	}
+
+	for i := range notifications {
+		w.attempt(ctx, &notifications[i])
+		if err := w.Storage.CompleteNotification(ctx, &notifications[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (w *Worker) attempt(ctx context.Context, notification *models.Notification) {
+	now := time.Now()
+	notification.Attempts++
+
+	err := w.send(ctx, notification)
+	if err == nil {
+		notification.Status = models.DeliveryDelivered
+		notification.SentAt = &now
+		notification.LastError = ""
+		return
+	}
+
+	notification.LastError = err.Error()
+	if notification.Attempts >= w.MaxAttempts {
+		notification.Status = models.DeliveryDead
+		return
+	}
+	notification.Status = models.DeliveryPending
+	notification.SendAt = now.Add(w.backoff(notification.Attempts))
+}
+
+func (w *Worker) send(ctx context.Context, notification *models.Notification) error {
+	mail, err := w.Renderer.Render(*notification)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+
+	return w.Mailer.Send(ctx, mail)
+}
+
+// backoff doubles the delay after every failed attempt, up to MaxBackoff.
+func (w *Worker) backoff(attempts int) time.Duration {
+	delay := w.BaseBackoff
+	for i := 1; i < attempts && delay < w.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > w.MaxBackoff {
+		delay = w.MaxBackoff
+	}
+	return delay
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+)
+
+// defaultExtension is how much a meeting is extended by when no duration
+// is given.
+const defaultExtension = 15 * time.Minute
+
+// EndReservation implements models.ReservationService. The reservation
+// ends now and the rest of its slot goes to the waitlist.
+func (r *reservationService) EndReservation(ctx context.Context, id int) (*models.Reservation, error) {
+	reservation, err := r.reservationStorage.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	freedUntil := reservation.EndTime
+	if err := r.endReservation(ctx, reservation); err != nil {
+		return nil, err
+	}
+
+	r.offerFreedSlot(ctx, reservation.RoomID, reservation.EndTime, freedUntil)
+	return reservation, nil
+}
+
+func (r *reservationService) endReservation(ctx context.Context, reservation *models.Reservation) error {
+	unlock, err := r.lockRoom(ctx, reservation.RoomID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	now := time.Now().Truncate(time.Second)
+	if reservation.Status != models.StatusApproved || !now.After(reservation.StartTime) || !now.Before(reservation.EndTime) {
+		return models.ErrReservationNotInProgress
+	}
+
+	reservation.EndTime = now
+	return r.reservationStorage.Update(ctx, reservation)
+}
+
+// ExtendReservation implements models.ReservationService. Only an
+// approved meeting in progress can be extended, and the extension must be
+// free: it is checked against reservations, holds and waitlist offers like
+// a new booking.
+func (r *reservationService) ExtendReservation(ctx context.Context, id int, by time.Duration) (*models.Reservation, error) {
+	if by == 0 {
+		by = defaultExtension
+	}
+	if by < 0 {
+		return nil, models.ErrInvalidParameter.WithField("minutes")
+	}
+
+	reservation, err := r.reservationStorage.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock, err := r.lockRoom(ctx, reservation.RoomID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	now := time.Now()
+	if reservation.Status != models.StatusApproved || now.Before(reservation.StartTime) || !now.Before(reservation.EndTime) {
+		return nil, models.ErrReservationNotInProgress
+	}
+	end := reservation.EndTime.Add(by)
+	if end.Sub(reservation.StartTime) > 24*time.Hour {
+		return nil, models.ErrReservationTimeExceedingLimit
+	}
+
+	extension := *reservation
+	extension.StartTime, extension.EndTime = reservation.EndTime, end
+	fits, err := r.fits(ctx, &extension, true, nil)
+	if err != nil {
+		return nil, err
+	}
+	if !fits {
+		return nil, r.conflictError(ctx, &extension)
+	}
+
+	reservation.EndTime = end
+	if err := r.reservationStorage.Update(ctx, reservation); err != nil {
+		return nil, err
+	}
+	return reservation, nil
+}
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"context"
+	"log"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+)
+
+// GetRoom implements models.ReservationService.
+func (r *reservationService) GetRoom(ctx context.Context, roomID string) (*models.Room, error) {
+	return r.reservationStorage.GetRoom(ctx, roomID)
+}
+
+// UpdateRoom implements models.ReservationService. New settings apply to
+// reservations made from now on, existing ones keep their status. Parts are
+// linked by setting their parent_id; combined rooms are one level deep, so
+// a parent cannot be a part itself and a room with parts cannot become one.
+func (r *reservationService) UpdateRoom(ctx context.Context, room *models.Room) error {
+	if room.ID == "" {
+		return models.ErrInvalidParameter.WithField("room_id")
+	}
+	if room.ParentID == room.ID {
+		return models.ErrInvalidParameter.WithField("parent_id")
+	}
+	if room.Capacity < 0 {
+		return models.ErrInvalidParameter.WithField("capacity")
+	}
+	if room.Type == "" {
+		room.Type = models.ResourceRoom
+	}
+	if !models.IsResourceType(room.Type) {
+		return models.ErrInvalidParameter.WithField("type")
+	}
+
+	current, err := r.reservationStorage.GetRoom(ctx, room.ID)
+	if err != nil {
+		return err
+	}
+	group := current.Group()
+
+	if room.ParentID != "" {
+		if len(current.Parts) > 0 {
+			return models.ErrInvalidParameter.WithField("parent_id")
+		}
+		parent, err := r.reservationStorage.GetRoom(ctx, room.ParentID)
+		if err != nil {
+			return err
+		}
+		if parent.ParentID != "" {
+			return models.ErrInvalidParameter.WithField("parent_id")
+		}
+		group = append(group, parent.Group()...)
+	}
+
+	unlock := r.lockRooms(group)
+	defer unlock()
+
+	room.Parts = current.Parts
+	return r.reservationStorage.SaveRoom(ctx, room)
+}
+
+// ListPending implements models.ReservationService.
+func (r *reservationService) ListPending(ctx context.Context, roomID string) ([]models.Reservation, error) {
+	return r.reservationStorage.ListPending(ctx, roomID)
+}
+
+// ApproveReservation implements models.ReservationService. In rooms where
+// pending requests do not block their slot the approved one must still be
+// free, and the requests that no longer fit next to it are rejected.
+func (r *reservationService) ApproveReservation(ctx context.Context, id int) (*models.Reservation, error) {
+	reservation, err := r.reservationStorage.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock, err := r.lockRoom(ctx, reservation.RoomID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	room, err := r.reservationStorage.GetRoom(ctx, reservation.RoomID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !room.PendingBlocks {
+		fits, err := r.fits(ctx, reservation, true, nil)
+		if err != nil {
+			return nil, err
+		}
+		if !fits {
+			return nil, r.conflictError(ctx, reservation)
+		}
+	}
+
+	if err := r.reservationStorage.SetStatus(ctx, reservation, models.StatusApproved); err != nil {
+		return nil, err
+	}
+
+	if !room.PendingBlocks {
+		r.rejectOverlapping(ctx, room, reservation)
+	}
+	return reservation, nil
+}
+
+// RejectReservation implements models.ReservationService. A pending
+// reservation that blocked its slot hands it to the waitlist.
+func (r *reservationService) RejectReservation(ctx context.Context, id int) (*models.Reservation, error) {
+	reservation, err := r.reservationStorage.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.setStatusLocked(ctx, reservation, models.StatusRejected); err != nil {
+		return nil, err
+	}
+
+	r.offerFreedSlot(ctx, reservation.RoomID, reservation.StartTime, reservation.EndTime)
+	return reservation, nil
+}
+
+func (r *reservationService) setStatusLocked(ctx context.Context, reservation *models.Reservation, status string) error {
+	unlock, err := r.lockRoom(ctx, reservation.RoomID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return r.reservationStorage.SetStatus(ctx, reservation, status)
+}
+
+// rejectOverlapping rejects the pending requests of the room and its linked
+// rooms that no longer fit next to an approved reservation. In a room with
+// a capacity an overlapping request may still fit and is left pending. The
+// approval stands either way, so failures are only logged.
+func (r *reservationService) rejectOverlapping(ctx context.Context, room *models.Room, approved *models.Reservation) {
+	for _, roomID := range room.Group() {
+		pending, err := r.reservationStorage.ListPending(ctx, roomID)
+		if err != nil {
+			log.Printf("Failed to load the pending reservations of room %s: %v", roomID, err)
+			continue
+		}
+
+		for i := range pending {
+			other := &pending[i]
+			if !other.StartTime.Before(approved.EndTime) || !other.EndTime.After(approved.StartTime) {
+				continue
+			}
+
+			fits, err := r.fits(ctx, other, false, nil)
+			if err != nil {
+				log.Printf("Failed to check reservation %d overlapping %d: %v", other.ID, approved.ID, err)
+				continue
+			}
+			if fits {
+				continue
+			}
+
+			if err := r.reservationStorage.SetStatus(ctx, other, models.StatusRejected); err != nil {
+				log.Printf("Failed to reject reservation %d overlapping %d: %v", other.ID, approved.ID, err)
+			}
+		}
+	}
+}
+
+// initialStatus is the status new reservations of a room start with.
+func (r *reservationService) initialStatus(ctx context.Context, roomID string) (string, error) {
+	room, err := r.reservationStorage.GetRoom(ctx, roomID)
+	if err != nil {
+		return "", err
+	}
+	return room.InitialStatus(), nil
+}
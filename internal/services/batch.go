@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+)
+
+// maxBatchSize caps the number of reservations booked together.
+const maxBatchSize = 50
+
+// CreateBatch implements models.ReservationService. All rooms of the batch
+// and the rooms linked to them are locked, in sorted order so concurrent
+// batches cannot deadlock, before any item is checked. Items take their
+// seats next to the earlier accepted items of the batch. Every item is
+// checked even after a failure so the error reports all of them.
+func (r *reservationService) CreateBatch(ctx context.Context, reservations []models.Reservation) error {
+	if len(reservations) == 0 || len(reservations) > maxBatchSize {
+		return models.ErrInvalidParameter.WithField("reservations")
+	}
+
+	results := make([]models.BatchItemResult, len(reservations))
+	failed := false
+	for i, reservation := range reservations {
+		results[i] = models.BatchItemResult{Index: i, Status: models.ImportAccepted}
+
+		err := TimeValidator(reservation.StartTime, reservation.EndTime)
+		if reservation.RoomID == "" {
+			err = models.ErrInvalidParameter.WithField("room_id")
+		}
+		if err != nil {
+			failed = true
+			results[i].Status = models.ImportInvalid
+			results[i].Detail = err.Error()
+			var domainErr *models.Error
+			if errors.As(err, &domainErr) {
+				results[i].Code = domainErr.Code
+				results[i].Field = domainErr.Field
+			}
+		}
+	}
+
+	rooms := map[string]*models.Room{}
+	var roomIDs []string
+	for i, reservation := range reservations {
+		if results[i].Status == models.ImportInvalid || rooms[reservation.RoomID] != nil {
+			continue
+		}
+		room, err := r.reservationStorage.GetRoom(ctx, reservation.RoomID)
+		if err != nil {
+			return err
+		}
+		rooms[reservation.RoomID] = room
+		roomIDs = append(roomIDs, room.Group()...)
+	}
+	unlock := r.lockRooms(roomIDs)
+	defer unlock()
+
+	var earlier []models.Reservation
+	for i := range reservations {
+		reservation := &reservations[i]
+		if results[i].Status == models.ImportInvalid {
+			continue
+		}
+
+		fits, err := r.fits(ctx, reservation, true, earlier)
+		if err != nil && !errors.Is(err, models.ErrInvalidParameter) {
+			return err
+		}
+		if err != nil {
+			failed = true
+			results[i].Status = models.ImportInvalid
+			results[i].Detail = err.Error()
+			var domainErr *models.Error
+			if errors.As(err, &domainErr) {
+				results[i].Code = domainErr.Code
+				results[i].Field = domainErr.Field
+			}
+			continue
+		}
+		if fits {
+			earlier = append(earlier, *reservation)
+			continue
+		}
+
+		for j, other := range reservations[:i] {
+			if results[j].Status == models.ImportAccepted && rooms[reservation.RoomID].Blocks(other.RoomID) &&
+				other.StartTime.Before(reservation.EndTime) && other.EndTime.After(reservation.StartTime) {
+				results[i].ConflictingItems = append(results[i].ConflictingItems, j)
+			}
+		}
+		results[i].Conflicts, err = r.reservationStorage.GetOverlapping(ctx, reservation.RoomID, reservation.StartTime, reservation.EndTime)
+		if err != nil {
+			return err
+		}
+
+		failed = true
+		results[i].Status = models.ImportConflict
+		results[i].Code = models.ErrRoomAlreadyReservated.Code
+		results[i].Detail = models.ErrRoomAlreadyReservated.Error()
+	}
+
+	if failed {
+		return &models.BatchError{Items: results}
+	}
+
+	for i := range reservations {
+		status, err := r.initialStatus(ctx, reservations[i].RoomID)
+		if err != nil {
+			return err
+		}
+		reservations[i].Status = status
+	}
+
+	return r.reservationStorage.CreateBatch(ctx, reservations)
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+)
+
+// fits reports whether reservation fits its room next to the stored
+// reservations and others, which are not stored yet. A room without a
+// capacity takes one reservation at a time; in a room with a capacity the
+// seats taken at the busiest moment of the interval plus the requested ones
+// must not exceed it. Holds and waitlist offers take one seat each and are
+// counted when held is set. Callers hold the room lock, so the answer stays
+// true until they write.
+func (r *reservationService) fits(ctx context.Context, reservation *models.Reservation, held bool, others []models.Reservation) (bool, error) {
+	room, err := r.reservationStorage.GetRoom(ctx, reservation.RoomID)
+	if err != nil {
+		return false, err
+	}
+
+	if reservation.Seats == 0 {
+		reservation.Seats = 1
+	}
+	if reservation.Seats < 0 || reservation.Seats > max(room.Capacity, 1) {
+		return false, models.ErrInvalidParameter.WithField("seats")
+	}
+
+	taken, err := r.reservationStorage.GetOverlapping(ctx, reservation.RoomID, reservation.StartTime, reservation.EndTime)
+	if err != nil {
+		return false, err
+	}
+	// an updated reservation never competes with its own previous interval
+	taken = excludeReservation(taken, reservation.ID)
+
+	if held {
+		holds, err := r.reservationStorage.GetHeld(ctx, reservation.RoomID, reservation.StartTime, reservation.EndTime)
+		if err != nil {
+			return false, err
+		}
+		taken = append(taken, holds...)
+	}
+
+	return seatsFree(room, append(taken, others...), reservation.StartTime, reservation.EndTime, reservation.Seats), nil
+}
+
+// seatsFree reports whether seats are free in room over the interval next
+// to the given reservations, of which only those of linked rooms that
+// overlap the interval count.
+func seatsFree(room *models.Room, reservations []models.Reservation, start, end time.Time, seats int) bool {
+	var taken []models.Reservation
+	for _, other := range reservations {
+		if room.Blocks(other.RoomID) && other.StartTime.Before(end) && other.EndTime.After(start) {
+			taken = append(taken, other)
+		}
+	}
+
+	return peakSeats(room, taken, start)+max(seats, 1) <= max(room.Capacity, 1)
+}
+
+// peakSeats returns the most seats of room the overlapping reservations take
+// at once from start on. The count only grows where a reservation starts,
+// so those are the moments checked. A reservation of a linked room takes
+// all seats.
+func peakSeats(room *models.Room, overlapping []models.Reservation, start time.Time) int {
+	seats := func(reservation models.Reservation) int {
+		switch {
+		case room.Capacity == 0:
+			return 1
+		case reservation.RoomID != room.ID:
+			return room.Capacity
+		}
+		return max(reservation.Seats, 1)
+	}
+
+	peak := 0
+	for _, reservation := range overlapping {
+		moment := reservation.StartTime
+		if moment.Before(start) {
+			moment = start
+		}
+
+		taken := 0
+		for _, other := range overlapping {
+			if !other.StartTime.After(moment) && other.EndTime.After(moment) {
+				taken += seats(other)
+			}
+		}
+		peak = max(peak, taken)
+	}
+	return peak
+}
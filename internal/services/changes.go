@@ -0,0 +1,39 @@
+package services
+
+import (
+	"context"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+)
+
+// GetChanges implements models.ReservationService.
+func (r *reservationService) GetChanges(ctx context.Context, since int64, limit int) (*models.ChangeFeed, error) {
+	if since < 0 {
+		return nil, models.ErrInvalidParameter.WithField("since")
+	}
+	if limit <= 0 {
+		limit = defaultPageSize
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+
+	changes, err := r.reservationStorage.GetChanges(ctx, since, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	feed := &models.ChangeFeed{
+		Changes:   changes,
+		NextSince: since,
+	}
+	if len(feed.Changes) > limit {
+		feed.Changes = feed.Changes[:limit]
+		feed.HasMore = true
+	}
+	if len(feed.Changes) > 0 {
+		feed.NextSince = feed.Changes[len(feed.Changes)-1].Seq
+	}
+
+	return feed, nil
+}
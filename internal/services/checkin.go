@@ -0,0 +1,49 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+)
+
+// checkInOpens is how long before the start a reservation can be checked
+// in to.
+const checkInOpens = 15 * time.Minute
+
+// CheckIn implements models.ReservationService. Checking in twice returns
+// the reservation as it was checked in the first time.
+func (r *reservationService) CheckIn(ctx context.Context, id int) (*models.Reservation, error) {
+	reservation, err := r.reservationStorage.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if reservation.CheckedInAt != nil {
+		return reservation, nil
+	}
+
+	now := time.Now()
+	if reservation.Status != models.StatusApproved || now.Before(reservation.StartTime.Add(-checkInOpens)) || !now.Before(reservation.EndTime) {
+		return nil, models.ErrCheckInNotOpen
+	}
+
+	if err := r.reservationStorage.CheckIn(ctx, reservation); err != nil {
+		return nil, err
+	}
+	return reservation, nil
+}
+
+// ReleaseNoShows implements models.ReservationService. What is left of a
+// released reservation goes to the waitlist.
+func (r *reservationService) ReleaseNoShows(ctx context.Context, grace time.Duration) error {
+	now := time.Now()
+	released, err := r.reservationStorage.ReleaseNoShows(ctx, now.Add(-grace))
+	if err != nil {
+		return err
+	}
+
+	for _, reservation := range released {
+		r.offerFreedSlot(ctx, reservation.RoomID, now, reservation.EndTime)
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+)
+
+const (
+	// subscriberBuffer is how many events a subscriber may lag behind
+	// before it is dropped and has to resume.
+	subscriberBuffer = 64
+
+	// replayPageSize is the page size EventsAfter reads the outbox with.
+	replayPageSize = 500
+
+	listenRetryDelay = 2 * time.Second
+
+	// catchUpInterval is how often the broker looks for events that were
+	// held back while an older transaction was still running.
+	catchUpInterval = time.Second
+)
+
+type subscription struct {
+	roomID string
+	events chan models.ReservationEvent
+}
+
+// EventBroker implements models.EventStream. It listens for committed
+// outbox events with one database connection per instance and fans them out
+// to the subscribers in memory.
+type EventBroker struct {
+	storage models.OutboxStorage
+
+	// cursorMu serializes catching up. cursor is the Seq of the last event
+	// dispatched.
+	cursorMu sync.Mutex
+	cursor   int64
+
+	mu          sync.Mutex
+	subscribers map[*subscription]struct{}
+}
+
+func NewEventBroker(storage models.OutboxStorage) *EventBroker {
+	return &EventBroker{
+		storage:     storage,
+		subscribers: make(map[*subscription]struct{}),
+	}
+}
+
+// Run listens for events until ctx is cancelled. Events committed while the
+// listener reconnects would be missed, so every subscription is closed and
+// clients resume from their last event.
+func (b *EventBroker) Run(ctx context.Context) {
+	for {
+		err := b.listen(ctx)
+		b.closeAll()
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("Event listener failed, reconnecting: %v", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(listenRetryDelay):
+		}
+	}
+}
+
+// listen dispatches events until the listener fails. A notification only
+// wakes the broker up: events are read in stream order after the last one
+// dispatched, also once every catchUpInterval to pick up the ones that were
+// held back.
+func (b *EventBroker) listen(ctx context.Context) error {
+	cursor, err := b.storage.LastSeq(ctx)
+	if err != nil {
+		return err
+	}
+	b.cursorMu.Lock()
+	b.cursor = cursor
+	b.cursorMu.Unlock()
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(catchUpInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				b.catchUp(ctx)
+			}
+		}
+	}()
+
+	return b.storage.Listen(ctx, func(int64) { b.catchUp(ctx) })
+}
+
+// Subscribe implements models.EventStream.
+func (b *EventBroker) Subscribe(roomID string) (<-chan models.ReservationEvent, func()) {
+	sub := &subscription{roomID: roomID, events: make(chan models.ReservationEvent, subscriberBuffer)}
+
+	b.mu.Lock()
+	b.subscribers[sub] = struct{}{}
+	b.mu.Unlock()
+
+	return sub.events, func() { b.remove(sub) }
+}
+
+// EventsAfter implements models.EventStream.
+func (b *EventBroker) EventsAfter(ctx context.Context, afterSeq int64, roomID string) ([]models.ReservationEvent, error) {
+	return b.storage.EventsAfter(ctx, afterSeq, roomID, replayPageSize)
+}
+
+// catchUp dispatches the events after the cursor.
+func (b *EventBroker) catchUp(ctx context.Context) {
+	b.cursorMu.Lock()
+	defer b.cursorMu.Unlock()
+
+	for {
+		events, err := b.storage.EventsAfter(ctx, b.cursor, "", replayPageSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("Failed to load events after %d: %v", b.cursor, err)
+			}
+			return
+		}
+		for _, event := range events {
+			b.dispatch(event)
+			b.cursor = event.Seq
+		}
+		if len(events) < replayPageSize {
+			return
+		}
+	}
+}
+
+func (b *EventBroker) dispatch(event models.ReservationEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for sub := range b.subscribers {
+		if sub.roomID != "" && sub.roomID != event.Reservation.RoomID {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			delete(b.subscribers, sub)
+			close(sub.events)
+		}
+	}
+}
+
+func (b *EventBroker) remove(sub *subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.subscribers[sub]; ok {
+		delete(b.subscribers, sub)
+		close(sub.events)
+	}
+}
+
+func (b *EventBroker) closeAll() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for sub := range b.subscribers {
+		delete(b.subscribers, sub)
+		close(sub.events)
+	}
+}
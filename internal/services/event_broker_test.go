@@ -0,0 +1,59 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models/mocks"
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestEventBroker(t *testing.T) {
+	events := []models.ReservationEvent{
+		{ID: 7, Seq: 11, Type: models.EventReservationCreated, Reservation: models.Reservation{ID: 1, RoomID: "411"}},
+		{ID: 6, Seq: 12, Type: models.EventReservationCreated, Reservation: models.Reservation{ID: 2, RoomID: "412"}},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	storage := mocks.NewOutboxStorage(t)
+	storage.On("LastSeq", mock.Anything).Return(int64(10), nil).Once()
+	storage.On("EventsAfter", mock.Anything, mock.Anything, "", mock.Anything).
+		Return(func(_ context.Context, afterSeq int64, _ string, _ int) ([]models.ReservationEvent, error) {
+			page := []models.ReservationEvent{}
+			for _, event := range events {
+				if event.Seq > afterSeq {
+					page = append(page, event)
+				}
+			}
+			return page, nil
+		})
+	storage.On("Listen", mock.Anything, mock.Anything).
+		Return(func(ctx context.Context, fn func(id int64)) error {
+			fn(7)
+			fn(6)
+			cancel()
+			return ctx.Err()
+		}).Once()
+
+	broker := services.NewEventBroker(storage)
+	room, _ := broker.Subscribe("411")
+	all, _ := broker.Subscribe("")
+
+	broker.Run(ctx)
+
+	collect := func(ch <-chan models.ReservationEvent) []int64 {
+		var ids []int64
+		for event := range ch {
+			ids = append(ids, event.Seq)
+		}
+		return ids
+	}
+	// Run closes every subscription when it stops listening.
+	assert.Equal(t, []int64{11}, collect(room))
+	assert.Equal(t, []int64{11, 12}, collect(all))
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+)
+
+const (
+	// maxFreeBusyRooms and maxFreeBusyWindow bound the work of one
+	// free/busy query.
+	maxFreeBusyRooms  = 100
+	maxFreeBusyWindow = 62 * 24 * time.Hour
+)
+
+// GetFreeBusy implements models.ReservationService.
+func (r *reservationService) GetFreeBusy(ctx context.Context, query models.FreeBusyQuery) (*models.FreeBusy, error) {
+	if query.StartTime.IsZero() || query.EndTime.IsZero() {
+		return nil, models.ErrTimeNotProvided
+	}
+	if !query.EndTime.After(query.StartTime) {
+		return nil, models.ErrEndTimeBeforeStartTime
+	}
+	if query.EndTime.Sub(query.StartTime) > maxFreeBusyWindow {
+		return nil, models.ErrInvalidParameter.WithField("end_time")
+	}
+
+	roomIDs := make([]string, 0, len(query.RoomIDs))
+	seen := make(map[string]bool)
+	for _, roomID := range query.RoomIDs {
+		if roomID == "" {
+			return nil, models.ErrInvalidParameter.WithField("room_ids")
+		}
+		if !seen[roomID] {
+			seen[roomID] = true
+			roomIDs = append(roomIDs, roomID)
+		}
+	}
+	if len(roomIDs) == 0 || len(roomIDs) > maxFreeBusyRooms {
+		return nil, models.ErrInvalidParameter.WithField("room_ids")
+	}
+
+	busy, err := r.reservationStorage.GetBusy(ctx, roomIDs, query.StartTime, query.EndTime)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.FreeBusy{
+		StartTime: query.StartTime,
+		EndTime:   query.EndTime,
+		Rooms:     make([]models.RoomBusy, 0, len(roomIDs)),
+	}
+	for _, roomID := range roomIDs {
+		slots := busy[roomID]
+		if slots == nil {
+			slots = []models.TimeSlot{}
+		}
+		result.Rooms = append(result.Rooms, models.RoomBusy{RoomID: roomID, Busy: slots})
+	}
+
+	return result, nil
+}
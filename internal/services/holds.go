@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+)
+
+const (
+	defaultHoldTTL = 5 * time.Minute
+	maxHoldTTL     = 30 * time.Minute
+)
+
+// CreateHold implements models.ReservationService. The hold is checked
+// against the same overlap invariant as a reservation.
+func (r *reservationService) CreateHold(ctx context.Context, hold *models.Hold) error {
+	if err := TimeValidator(hold.StartTime, hold.EndTime); err != nil {
+		return err
+	}
+	if hold.RoomID == "" {
+		return models.ErrInvalidParameter.WithField("room_id")
+	}
+
+	ttl := time.Duration(hold.TTLSeconds) * time.Second
+	if ttl == 0 {
+		ttl = defaultHoldTTL
+	}
+	if ttl < 0 || ttl > maxHoldTTL {
+		return models.ErrInvalidParameter.WithField("ttl_seconds")
+	}
+
+	unlock, err := r.lockRoom(ctx, hold.RoomID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	reservation := hold.Reservation()
+	fits, err := r.fits(ctx, &reservation, true, nil)
+	if err != nil {
+		return err
+	}
+	if !fits {
+		return r.conflictError(ctx, &reservation)
+	}
+
+	hold.TTLSeconds = int(ttl / time.Second)
+	hold.ExpiresAt = time.Now().Add(ttl)
+	return r.reservationStorage.CreateHold(ctx, hold)
+}
+
+// GetHold implements models.ReservationService.
+func (r *reservationService) GetHold(ctx context.Context, id int) (*models.Hold, error) {
+	return r.reservationStorage.GetHold(ctx, id)
+}
+
+// ConfirmHold implements models.ReservationService. The hold kept its
+// interval free, so only reservations that slipped in through an update
+// can still conflict. A hold whose interval has started can no longer be
+// confirmed, like a reservation cannot be made in the past.
+func (r *reservationService) ConfirmHold(ctx context.Context, id int) (*models.Reservation, error) {
+	hold, err := r.reservationStorage.GetHold(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if hold.Status != models.HoldActive || !hold.ExpiresAt.After(now) {
+		return nil, models.ErrHoldNotActive
+	}
+	if hold.StartTime.Before(now) {
+		return nil, models.ErrPastTime
+	}
+
+	unlock, err := r.lockRoom(ctx, hold.RoomID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	reservation := hold.Reservation()
+	fits, err := r.fits(ctx, &reservation, false, nil)
+	if err != nil {
+		return nil, err
+	}
+	if !fits {
+		return nil, r.conflictError(ctx, &reservation)
+	}
+
+	reservation.Status, err = r.initialStatus(ctx, reservation.RoomID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.reservationStorage.ConfirmHold(ctx, hold, &reservation); err != nil {
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+// ReleaseHold implements models.ReservationService. The released interval
+// goes to the waitlist.
+func (r *reservationService) ReleaseHold(ctx context.Context, id int) error {
+	hold, err := r.reservationStorage.ReleaseHold(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	r.offerFreedSlot(ctx, hold.RoomID, hold.StartTime, hold.EndTime)
+	return nil
+}
+
+// ExpireHolds implements models.ReservationService.
+func (r *reservationService) ExpireHolds(ctx context.Context) error {
+	expired, err := r.reservationStorage.ExpireHolds(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, hold := range expired {
+		r.offerFreedSlot(ctx, hold.RoomID, hold.StartTime, hold.EndTime)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+)
+
+// Import implements models.ReservationService. Every row goes through
+// TimeValidator and the availability check against stored reservations,
+// active holds and waitlist offers, and rows accepted earlier in the same
+// import. Rows for rooms that require
+// approval are imported as pending requests. In dry-run mode nothing is
+// written.
+func (r *reservationService) Import(ctx context.Context, rows []models.ImportRow, dryRun bool) (*models.ImportReport, error) {
+	report := &models.ImportReport{
+		DryRun:  dryRun,
+		Results: make([]models.ImportResult, 0, len(rows)),
+	}
+	accepted := map[string][]models.Reservation{}
+
+	for _, row := range rows {
+		result, err := r.importRow(ctx, row, accepted, dryRun)
+		if err != nil {
+			return nil, err
+		}
+
+		switch result.Status {
+		case models.ImportAccepted:
+			report.Accepted++
+		case models.ImportConflict:
+			report.Conflicts++
+		case models.ImportInvalid:
+			report.Invalid++
+		}
+		report.Results = append(report.Results, result)
+	}
+
+	return report, nil
+}
+
+func (r *reservationService) importRow(ctx context.Context, row models.ImportRow, accepted map[string][]models.Reservation, dryRun bool) (models.ImportResult, error) {
+	result := models.ImportResult{Line: row.Line}
+	reservation := row.Reservation
+
+	err := row.Err
+	if err == nil {
+		err = TimeValidator(reservation.StartTime, reservation.EndTime)
+	}
+	if err != nil {
+		result.Status = models.ImportInvalid
+		result.Detail = err.Error()
+		var domainErr *models.Error
+		if errors.As(err, &domainErr) {
+			result.Code = domainErr.Code
+		}
+		return result, nil
+	}
+
+	room, err := r.reservationStorage.GetRoom(ctx, reservation.RoomID)
+	if err != nil {
+		return result, err
+	}
+	unlock := r.lockRooms(room.Group())
+	defer unlock()
+
+	// in dry-run mode rows accepted earlier are not stored
+	var pending []models.Reservation
+	if dryRun {
+		for _, roomID := range room.Group() {
+			pending = append(pending, accepted[roomID]...)
+		}
+	}
+
+	fits, err := r.fits(ctx, &reservation, true, pending)
+	if err != nil {
+		return result, err
+	}
+
+	if !fits {
+		conflicts, err := r.reservationStorage.GetOverlapping(ctx, reservation.RoomID, reservation.StartTime, reservation.EndTime)
+		if err != nil {
+			return result, err
+		}
+		for _, other := range pending {
+			if other.StartTime.Before(reservation.EndTime) && other.EndTime.After(reservation.StartTime) {
+				conflicts = append(conflicts, other)
+			}
+		}
+
+		result.Status = models.ImportConflict
+		result.Code = models.ErrRoomAlreadyReservated.Code
+		result.Detail = models.ErrRoomAlreadyReservated.Error()
+		result.Conflicts = conflicts
+		return result, nil
+	}
+
+	reservation.Status = room.InitialStatus()
+	if !dryRun {
+		if err := r.reservationStorage.Create(ctx, &reservation); err != nil {
+			return result, err
+		}
+	}
+	accepted[reservation.RoomID] = append(accepted[reservation.RoomID], reservation)
+
+	result.Status = models.ImportAccepted
+	result.Reservation = &reservation
+	return result, nil
+}
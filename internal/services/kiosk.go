@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+)
+
+const (
+	// roomStatusHorizon is how far ahead the next reservation of a room is
+	// looked for.
+	roomStatusHorizon = 24 * time.Hour
+
+	defaultQuickBooking = 30 * time.Minute
+)
+
+// GetRoomStatus implements models.ReservationService. Reservations that do
+// not block the room, such as pending requests that may overlap, are not
+// shown.
+func (r *reservationService) GetRoomStatus(ctx context.Context, roomID string) (*models.RoomStatus, error) {
+	now := time.Now()
+	reservations, err := r.reservationStorage.GetOverlapping(ctx, roomID, now, now.Add(roomStatusHorizon))
+	if err != nil {
+		return nil, err
+	}
+
+	status := &models.RoomStatus{RoomID: roomID, AsOf: now}
+	for i := range reservations {
+		reservation := &reservations[i]
+		switch {
+		case !reservation.StartTime.After(now):
+			status.Current = reservation
+		case status.Next == nil:
+			status.Next = reservation
+		}
+	}
+
+	status.Occupied = status.Current != nil
+	if !status.Occupied && status.Next != nil {
+		status.FreeUntil = &status.Next.StartTime
+	}
+	return status, nil
+}
+
+// QuickBook implements models.ReservationService. The booking is made at
+// the door, so it is stored checked in unless it awaits approval.
+func (r *reservationService) QuickBook(ctx context.Context, roomID, userID string, duration time.Duration) (*models.Reservation, error) {
+	if duration == 0 {
+		duration = defaultQuickBooking
+	}
+	if duration < 0 {
+		return nil, models.ErrInvalidParameter.WithField("minutes")
+	}
+	if duration > 24*time.Hour {
+		return nil, models.ErrReservationTimeExceedingLimit
+	}
+
+	now := time.Now().Truncate(time.Second)
+	reservation := &models.Reservation{
+		RoomID:    roomID,
+		UserID:    userID,
+		StartTime: now,
+		EndTime:   now.Add(duration),
+	}
+
+	unlock, err := r.lockRoom(ctx, roomID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	fits, err := r.fits(ctx, reservation, true, nil)
+	if err != nil {
+		return nil, err
+	}
+	if !fits {
+		return nil, r.conflictError(ctx, reservation)
+	}
+
+	reservation.Status, err = r.initialStatus(ctx, roomID)
+	if err != nil {
+		return nil, err
+	}
+	if reservation.Status == models.StatusApproved {
+		reservation.CheckedInAt = &now
+	}
+	if err := r.reservationStorage.Create(ctx, reservation); err != nil {
+		return nil, err
+	}
+	return reservation, nil
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+)
+
+type notificationService struct {
+	notificationStorage models.NotificationStorage
+	reminderLead        time.Duration
+	userDomain          string
+}
+
+// Publish implements models.EventPublisher by queueing an email to the
+// booking user and keeping their reminder in step with the reservation.
+// Users without an address and check-ins, which the user made themselves,
+// are skipped.
+func (s *notificationService) Publish(ctx context.Context, event models.ReservationEvent) error {
+	recipient := s.recipient(event.Reservation.UserID)
+	if recipient == "" || event.Type == models.EventReservationCheckedIn {
+		return nil
+	}
+
+	now := time.Now()
+	err := s.notificationStorage.Enqueue(ctx, &models.Notification{
+		EventID:     event.ID,
+		Kind:        event.Type,
+		Recipient:   recipient,
+		Reservation: event.Reservation,
+		Waitlist:    event.Waitlist,
+		SendAt:      now,
+	})
+	if err != nil {
+		return err
+	}
+
+	if s.reminderLead <= 0 || event.Type == models.EventWaitlistOffered {
+		return nil
+	}
+
+	// pending reservations get their reminder once approved
+	remindAt := event.Reservation.StartTime.Add(-s.reminderLead)
+	switch {
+	case event.Type == models.EventReservationCancelled,
+		event.Type == models.EventReservationRejected,
+		event.Reservation.Status == models.StatusPending,
+		!remindAt.After(now):
+		return s.notificationStorage.CancelReminder(ctx, event.Reservation.ID)
+	}
+
+	return s.notificationStorage.ScheduleReminder(ctx, &models.Notification{
+		Kind:        models.NotificationReminder,
+		Recipient:   recipient,
+		Reservation: event.Reservation,
+		SendAt:      remindAt,
+	})
+}
+
+// recipient takes user IDs that are addresses as they are and completes
+// the others with userDomain when it is set.
+func (s *notificationService) recipient(userID string) string {
+	switch {
+	case strings.HasPrefix(userID, models.DeviceUserPrefix):
+		return ""
+	case strings.Contains(userID, "@"):
+		return userID
+	case userID != "" && s.userDomain != "":
+		return userID + "@" + s.userDomain
+	default:
+		return ""
+	}
+}
+
+// NewNotificationService returns the sink that emails booking users about
+// their reservations and reminds them reminderLead before the start. A zero
+// reminderLead disables reminders.
+func NewNotificationService(storage models.NotificationStorage, reminderLead time.Duration, userDomain string) models.EventPublisher {
+	return &notificationService{
+		notificationStorage: storage,
+		reminderLead:        reminderLead,
+		userDomain:          userDomain,
+	}
+}
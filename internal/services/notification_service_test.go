@@ -0,0 +1,85 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models/mocks"
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationService(t *testing.T) {
+	ctx := context.Background()
+	start := time.Now().Add(24 * time.Hour).Truncate(time.Minute)
+	event := func(eventType, userID string, start time.Time) models.ReservationEvent {
+		return models.ReservationEvent{ID: 5, Type: eventType, Reservation: models.Reservation{
+			ID: 7, RoomID: "411", UserID: userID, StartTime: start, EndTime: start.Add(time.Hour),
+		}}
+	}
+
+	t.Run("queues the email and schedules a reminder", func(t *testing.T) {
+		storage := mocks.NewNotificationStorage(t)
+		storage.On("Enqueue", mock.Anything, mock.MatchedBy(func(n *models.Notification) bool {
+			return n.EventID == 5 && n.Kind == models.EventReservationCreated && n.Recipient == "alice@corp.example"
+		})).Return(nil)
+		storage.On("ScheduleReminder", mock.Anything, mock.MatchedBy(func(n *models.Notification) bool {
+			return n.Kind == models.NotificationReminder && n.SendAt.Equal(start.Add(-15*time.Minute))
+		})).Return(nil)
+
+		service := services.NewNotificationService(storage, 15*time.Minute, "corp.example")
+		require.NoError(t, service.Publish(ctx, event(models.EventReservationCreated, "alice", start)))
+	})
+
+	t.Run("cancellation drops the reminder", func(t *testing.T) {
+		storage := mocks.NewNotificationStorage(t)
+		storage.On("Enqueue", mock.Anything, mock.Anything).Return(nil)
+		storage.On("CancelReminder", mock.Anything, 7).Return(nil)
+
+		service := services.NewNotificationService(storage, 15*time.Minute, "")
+		require.NoError(t, service.Publish(ctx, event(models.EventReservationCancelled, "bob@example.com", start)))
+	})
+
+	t.Run("pending reservations are reminded once approved", func(t *testing.T) {
+		storage := mocks.NewNotificationStorage(t)
+		storage.On("Enqueue", mock.Anything, mock.Anything).Return(nil)
+		storage.On("CancelReminder", mock.Anything, 7).Return(nil).Once()
+		storage.On("ScheduleReminder", mock.Anything, mock.Anything).Return(nil).Once()
+
+		service := services.NewNotificationService(storage, 15*time.Minute, "")
+		pending := event(models.EventReservationCreated, "bob@example.com", start)
+		pending.Reservation.Status = models.StatusPending
+		require.NoError(t, service.Publish(ctx, pending))
+
+		approved := event(models.EventReservationApproved, "bob@example.com", start)
+		approved.Reservation.Status = models.StatusApproved
+		require.NoError(t, service.Publish(ctx, approved))
+	})
+
+	t.Run("no reminder for a reservation starting too soon", func(t *testing.T) {
+		storage := mocks.NewNotificationStorage(t)
+		storage.On("Enqueue", mock.Anything, mock.Anything).Return(nil)
+		storage.On("CancelReminder", mock.Anything, 7).Return(nil)
+
+		service := services.NewNotificationService(storage, 15*time.Minute, "")
+		require.NoError(t, service.Publish(ctx, event(models.EventReservationUpdated, "bob@example.com", time.Now().Add(5*time.Minute))))
+	})
+
+	t.Run("users without an address are skipped", func(t *testing.T) {
+		storage := mocks.NewNotificationStorage(t)
+
+		service := services.NewNotificationService(storage, 15*time.Minute, "")
+		assert.NoError(t, service.Publish(ctx, event(models.EventReservationCreated, "alice", start)))
+	})
+
+	t.Run("door display bookings are skipped", func(t *testing.T) {
+		storage := mocks.NewNotificationStorage(t)
+
+		service := services.NewNotificationService(storage, 15*time.Minute, "corp.example")
+		assert.NoError(t, service.Publish(ctx, event(models.EventReservationCreated, "device:411", start)))
+	})
+}
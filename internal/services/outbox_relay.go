@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+)
+
+// OutboxRelay publishes the events storage writes to the outbox together
+// with every reservation change. Delivery is at least once: an event is
+// marked published only after every sink accepted it, and is sent again to
+// all of them otherwise. Events of one room are published in the order they
+// were written, a failed event holds back the rest of its room until the
+// lease on its claim runs out. Sinks are called outside any database
+// transaction.
+type OutboxRelay struct {
+	Storage      models.OutboxStorage
+	Sinks        []models.EventPublisher
+	PollInterval time.Duration
+	BatchSize    int
+	Lease        time.Duration
+}
+
+func NewOutboxRelay(storage models.OutboxStorage, sinks ...models.EventPublisher) *OutboxRelay {
+	return &OutboxRelay{
+		Storage:      storage,
+		Sinks:        sinks,
+		PollInterval: time.Second,
+		BatchSize:    100,
+		Lease:        30 * time.Second,
+	}
+}
+
+// Run relays events until ctx is cancelled.
+func (r *OutboxRelay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := r.RunOnce(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Outbox relay failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce publishes one batch of pending events.
+func (r *OutboxRelay) RunOnce(ctx context.Context) error {
+	events, err := r.Storage.ClaimEvents(ctx, r.BatchSize, r.Lease)
+	if err != nil || len(events) == 0 {
+		return err
+	}
+
+	published := r.publish(ctx, events)
+	if len(published) == 0 {
+		return nil
+	}
+	return r.Storage.MarkPublished(context.WithoutCancel(ctx), published)
+}
+
+func (r *OutboxRelay) publish(ctx context.Context, events []models.ReservationEvent) []int64 {
+	blocked := make(map[string]bool)
+	published := make([]int64, 0, len(events))
+
+	for _, event := range events {
+		roomID := event.Reservation.RoomID
+		if blocked[roomID] {
+			continue
+		}
+
+		if err := r.publishEvent(ctx, event); err != nil {
+			log.Printf("Failed to publish event %d for room %s: %v", event.ID, roomID, err)
+			blocked[roomID] = true
+			continue
+		}
+		published = append(published, event.ID)
+	}
+
+	return published
+}
+
+func (r *OutboxRelay) publishEvent(ctx context.Context, event models.ReservationEvent) error {
+	for _, sink := range r.Sinks {
+		if err := sink.Publish(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
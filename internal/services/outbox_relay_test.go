@@ -0,0 +1,57 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models/mocks"
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutboxRelay(t *testing.T) {
+	events := []models.ReservationEvent{
+		{ID: 1, Type: models.EventReservationCreated, Reservation: models.Reservation{ID: 1, RoomID: "411"}},
+		{ID: 2, Type: models.EventReservationCreated, Reservation: models.Reservation{ID: 2, RoomID: "412"}},
+		{ID: 3, Type: models.EventReservationUpdated, Reservation: models.Reservation{ID: 1, RoomID: "411"}},
+		{ID: 4, Type: models.EventReservationCancelled, Reservation: models.Reservation{ID: 2, RoomID: "412"}},
+	}
+
+	var published []int64
+	storage := mocks.NewOutboxStorage(t)
+	storage.On("ClaimEvents", mock.Anything, 100, 30*time.Second).Return(events, nil)
+	storage.On("MarkPublished", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		published = args.Get(1).([]int64)
+	}).Return(nil)
+
+	var sent []int64
+	sink := publisherFunc(func(event models.ReservationEvent) error {
+		if event.ID == 1 {
+			return errors.New("sink is down")
+		}
+		sent = append(sent, event.ID)
+		return nil
+	})
+
+	relay := services.NewOutboxRelay(storage, sink)
+	require.NoError(t, relay.RunOnce(context.Background()))
+
+	assert.Equal(t, []int64{2, 4}, published, "a failed event holds back later events of its room only")
+	assert.Equal(t, []int64{2, 4}, sent)
+}
+
+func TestOutboxRelayNothingClaimed(t *testing.T) {
+	storage := mocks.NewOutboxStorage(t)
+	storage.On("ClaimEvents", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+
+	relay := services.NewOutboxRelay(storage, publisherFunc(func(models.ReservationEvent) error {
+		t.Fatal("nothing should be published")
+		return nil
+	}))
+	require.NoError(t, relay.RunOnce(context.Background()))
+}
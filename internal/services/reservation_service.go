@@ -2,12 +2,26 @@ package services
 
 import (
 	"context"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
 )
 
+const (
+	// suggestionsLimit caps the number of alternative slots and rooms
+	// returned alongside a conflict.
+	suggestionsLimit = 3
+
+	defaultPageSize = 100
+	maxPageSize     = 500
+
+	// calendarHistory is how far back calendar feeds include past and
+	// cancelled reservations.
+	calendarHistory = 30 * 24 * time.Hour
+)
+
 type reservationService struct {
 	reservationStorage models.ReservationStorage
 	contextTimeout     time.Duration
@@ -38,20 +52,24 @@ func (r *reservationService) Create(ctx context.Context, reservation *models.Res
 		return err
 	}
 
-	roomMutex := r.getRoomMutex(reservation.RoomID)
-
-	roomMutex.Lock()
-
-	defer roomMutex.Unlock()
-
+	unlock, err := r.lockRoom(ctx, reservation.RoomID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
 
-	isReserved, err := r.reservationStorage.IsReserved(ctx, reservation.RoomID, reservation.StartTime, reservation.EndTime)
+	fits, err := r.fits(ctx, reservation, true, nil)
 	if err != nil {
 		return err
 	}
 
-	if isReserved {
-		return models.ErrRoomAlreadyReservated
+	if !fits {
+		return r.conflictError(ctx, reservation)
+	}
+
+	reservation.Status, err = r.initialStatus(ctx, reservation.RoomID)
+	if err != nil {
+		return err
 	}
 
 	return r.reservationStorage.Create(ctx, reservation)
@@ -62,12 +80,19 @@ func (r *reservationService) DeleteReservation(ctx context.Context, reservation
 	if err := r.reservationStorage.DeleteReservation(ctx, reservation); err != nil{
 		return err
 	}
+	r.offerFreedSlot(ctx, reservation.RoomID, reservation.StartTime, reservation.EndTime)
 	return nil
 }
 
-// GetByRoomID implements models.ReservationService.
-func (r *reservationService) GetByRoomID(ctx context.Context, roomID string) (*models.RoomReservations, error) {
-	reservations, err := r.reservationStorage.GetByRoomID(ctx, roomID)
+// GetByRoomID implements models.ReservationService. Without an explicit
+// range only upcoming and ongoing reservations are listed.
+func (r *reservationService) GetByRoomID(ctx context.Context, roomID string, filter models.ReservationFilter) (*models.RoomReservations, error) {
+	filter, err := normalizeFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	reservations, err := r.reservationStorage.GetByRoomID(ctx, roomID, filter)
 	if err != nil {
 		return nil, err
 	}
@@ -77,6 +102,93 @@ func (r *reservationService) GetByRoomID(ctx context.Context, roomID string) (*m
 
 
 
+// StreamByRoomID implements models.ReservationService. begin receives the
+// page summary before the first reservation is passed to fn, so callers can
+// send headers derived from it.
+func (r *reservationService) StreamByRoomID(ctx context.Context, roomID string, filter models.ReservationFilter, begin func(models.ListingInfo) error, fn func(models.Reservation) error) error {
+	filter, err := normalizeFilter(filter)
+	if err != nil {
+		return err
+	}
+
+	return r.reservationStorage.StreamByRoomID(ctx, roomID, filter, begin, fn)
+}
+
+// normalizeFilter applies the listing defaults: upcoming reservations only
+// and a bounded page size.
+func normalizeFilter(filter models.ReservationFilter) (models.ReservationFilter, error) {
+	if filter.From.IsZero() {
+		filter.From = time.Now()
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultPageSize
+	}
+	if filter.Limit > maxPageSize {
+		filter.Limit = maxPageSize
+	}
+	if !filter.To.IsZero() && !filter.To.After(filter.From) {
+		return filter, models.ErrInvalidParameter.WithField("to")
+	}
+	return filter, nil
+}
+
+// GetByID implements models.ReservationService.
+func (r *reservationService) GetByID(ctx context.Context, id int) (*models.Reservation, error) {
+	return r.reservationStorage.GetByID(ctx, id)
+}
+
+// GetByICalUID implements models.ReservationService.
+func (r *reservationService) GetByICalUID(ctx context.Context, roomID, uid string) (*models.Reservation, error) {
+	return r.reservationStorage.GetByICalUID(ctx, roomID, uid)
+}
+
+// Update implements models.ReservationService. Zero reservation.Seats keeps
+// the booked seats.
+func (r *reservationService) Update(ctx context.Context, reservation *models.Reservation) error {
+	err := TimeValidator(reservation.StartTime, reservation.EndTime)
+	if err != nil {
+		return err
+	}
+
+	unlock, err := r.lockRoom(ctx, reservation.RoomID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if reservation.Seats == 0 {
+		stored, err := r.reservationStorage.GetByID(ctx, reservation.ID)
+		if err != nil {
+			return err
+		}
+		reservation.Seats = stored.Seats
+	}
+
+	fits, err := r.fits(ctx, reservation, true, nil)
+	if err != nil {
+		return err
+	}
+	if !fits {
+		return r.conflictError(ctx, reservation)
+	}
+
+	return r.reservationStorage.Update(ctx, reservation)
+}
+
+// DeleteByID implements models.ReservationService.
+func (r *reservationService) DeleteByID(ctx context.Context, reservation *models.Reservation) error {
+	if err := r.reservationStorage.DeleteByID(ctx, reservation); err != nil {
+		return err
+	}
+	r.offerFreedSlot(ctx, reservation.RoomID, reservation.StartTime, reservation.EndTime)
+	return nil
+}
+
+// GetCalendar implements models.ReservationService.
+func (r *reservationService) GetCalendar(ctx context.Context, feed models.CalendarFeed) ([]models.Reservation, error) {
+	return r.reservationStorage.GetCalendar(ctx, feed, time.Now().Add(-calendarHistory))
+}
+
 func NewReservationService(reservationStorage models.ReservationStorage, timeout time.Duration) models.ReservationService {
 	return &reservationService{
 		reservationStorage: reservationStorage,
@@ -100,3 +212,147 @@ func (r *reservationService) getRoomMutex(roomID string) *sync.Mutex {
 	r.mutexes[roomID] = mutex
 	return mutex
 }
+
+// lockRoom locks a room together with the rooms it is combined with and
+// returns the function that unlocks them.
+func (r *reservationService) lockRoom(ctx context.Context, roomID string) (func(), error) {
+	room, err := r.reservationStorage.GetRoom(ctx, roomID)
+	if err != nil {
+		return nil, err
+	}
+	return r.lockRooms(room.Group()), nil
+}
+
+// lockRooms locks the mutexes of the given rooms in sorted order and
+// returns the function that unlocks them.
+func (r *reservationService) lockRooms(roomIDs []string) func() {
+	unique := make([]string, 0, len(roomIDs))
+	seen := map[string]bool{}
+	for _, roomID := range roomIDs {
+		if !seen[roomID] {
+			seen[roomID] = true
+			unique = append(unique, roomID)
+		}
+	}
+	sort.Strings(unique)
+
+	locked := make([]func(), 0, len(unique))
+	for _, roomID := range unique {
+		mutex := r.getRoomMutex(roomID)
+		mutex.Lock()
+		locked = append(locked, mutex.Unlock)
+	}
+
+	return func() {
+		for i := len(locked) - 1; i >= 0; i-- {
+			locked[i]()
+		}
+	}
+}
+
+// conflictError builds the error returned for an overlapping reservation,
+// including alternative slots in the same room and free rooms at the
+// requested time.
+func (r *reservationService) conflictError(ctx context.Context, reservation *models.Reservation) error {
+	conflicts, err := r.reservationStorage.GetOverlapping(ctx, reservation.RoomID, reservation.StartTime, reservation.EndTime)
+	if err != nil {
+		return err
+	}
+
+	room, err := r.reservationStorage.GetRoom(ctx, reservation.RoomID)
+	if err != nil {
+		return err
+	}
+
+	requested := models.TimeSlot{StartTime: reservation.StartTime, EndTime: reservation.EndTime}
+	window := reservation.EndTime.Sub(reservation.StartTime) + 24*time.Hour
+	from, to := requested.StartTime.Add(-window), requested.EndTime.Add(window)
+	busy, err := r.reservationStorage.GetOverlapping(ctx, reservation.RoomID, from, to)
+	if err != nil {
+		return err
+	}
+
+	// an updated reservation never conflicts with its own previous interval
+	conflicts = excludeReservation(conflicts, reservation.ID)
+	busy = excludeReservation(busy, reservation.ID)
+
+	// holds and waitlist offers have no id, so they are added afterwards
+	held, err := r.reservationStorage.GetHeld(ctx, reservation.RoomID, from, to)
+	if err != nil {
+		return err
+	}
+	busy = append(busy, held...)
+
+	free := func(slot models.TimeSlot) bool {
+		return seatsFree(room, busy, slot.StartTime, slot.EndTime, reservation.Seats)
+	}
+
+	rooms, err := r.reservationStorage.GetFreeRooms(ctx, reservation.RoomID, reservation.StartTime, reservation.EndTime, suggestionsLimit)
+	if err != nil {
+		return err
+	}
+
+	return &models.ConflictError{
+		Conflicts:      conflicts,
+		SuggestedSlots: suggestSlots(busy, requested, time.Now(), suggestionsLimit, free),
+		SuggestedRooms: rooms,
+	}
+}
+
+// suggestSlots returns up to limit slots of the requested duration that
+// start right after or end right before a busy reservation, nearest first.
+// Only slots free reports as bookable are suggested.
+func suggestSlots(busy []models.Reservation, requested models.TimeSlot, now time.Time, limit int, free func(models.TimeSlot) bool) []models.TimeSlot {
+	duration := requested.EndTime.Sub(requested.StartTime)
+
+	var candidates []models.TimeSlot
+	for _, b := range busy {
+		candidates = append(candidates,
+			models.TimeSlot{StartTime: b.EndTime, EndTime: b.EndTime.Add(duration)},
+			models.TimeSlot{StartTime: b.StartTime.Add(-duration), EndTime: b.StartTime},
+		)
+	}
+
+	distance := func(slot models.TimeSlot) time.Duration {
+		d := slot.StartTime.Sub(requested.StartTime)
+		if d < 0 {
+			return -d
+		}
+		return d
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return distance(candidates[i]) < distance(candidates[j])
+	})
+
+	suggestions := []models.TimeSlot{}
+	for _, candidate := range candidates {
+		if len(suggestions) == limit {
+			break
+		}
+		if candidate.StartTime.Before(now) || containsSlot(suggestions, candidate) || !free(candidate) {
+			continue
+		}
+		suggestions = append(suggestions, candidate)
+	}
+
+	return suggestions
+}
+
+func excludeReservation(reservations []models.Reservation, id int) []models.Reservation {
+	filtered := reservations[:0]
+	for _, reservation := range reservations {
+		if reservation.ID != id {
+			filtered = append(filtered, reservation)
+		}
+	}
+	return filtered
+}
+
+func containsSlot(slots []models.TimeSlot, slot models.TimeSlot) bool {
+	for _, s := range slots {
+		if s.StartTime.Equal(slot.StartTime) && s.EndTime.Equal(slot.EndTime) {
+			return true
+		}
+	}
+	return false
+}
@@ -2,6 +2,7 @@ package services_test
 
 import (
 	"context"
+	"errors"
 	"sync"
 	"testing"
 	"time"
@@ -78,6 +79,41 @@ func TestReservationServiceCreate(t *testing.T) {
 
 		err = service.Create(ctx, reservationSecond)
 		assert.ErrorIs(t, err, models.ErrRoomAlreadyReservated)
+
+		var conflictErr *models.ConflictError
+		require.ErrorAs(t, err, &conflictErr)
+		assert.Len(t, conflictErr.Conflicts, 1)
+	})
+
+	t.Run("conflict suggests alternatives", func(t *testing.T) {
+		_, err := db.Exec(ctx, "DELETE FROM reservations")
+		require.NoError(t, err)
+
+		startTime := time.Now().Add(2 * time.Hour).Truncate(time.Minute)
+		existing := &models.Reservation{
+			RoomID:    "412",
+			StartTime: startTime,
+			EndTime:   startTime.Add(1 * time.Hour),
+		}
+		otherRoom := &models.Reservation{
+			RoomID:    "413",
+			StartTime: startTime.Add(5 * time.Hour),
+			EndTime:   startTime.Add(6 * time.Hour),
+		}
+		require.NoError(t, service.Create(ctx, existing))
+		require.NoError(t, service.Create(ctx, otherRoom))
+
+		err = service.Create(ctx, &models.Reservation{
+			RoomID:    "412",
+			StartTime: startTime.Add(30 * time.Minute),
+			EndTime:   startTime.Add(90 * time.Minute),
+		})
+
+		var conflictErr *models.ConflictError
+		require.ErrorAs(t, err, &conflictErr)
+		require.NotEmpty(t, conflictErr.SuggestedSlots)
+		assert.True(t, existing.EndTime.Equal(conflictErr.SuggestedSlots[0].StartTime))
+		assert.Equal(t, []string{"413"}, conflictErr.SuggestedRooms)
 	})
 
 	t.Run("time not provided", func(t *testing.T) {
@@ -199,7 +235,7 @@ func TestConcurrentReservations(t *testing.T) {
 
 			if err == nil {
 				successCount++
-			} else if err == models.ErrRoomAlreadyReservated {
+			} else if errors.Is(err, models.ErrRoomAlreadyReservated) {
 				failureCount++
 			} else {
 				t.Error(err)
@@ -259,7 +295,7 @@ func TestConcurrentReservationsWithDifferentPayloads(t *testing.T) {
 
 			if err == nil {
 				successCount++
-			} else if err == models.ErrRoomAlreadyReservated {
+			} else if errors.Is(err, models.ErrRoomAlreadyReservated) {
 				failureCount++
 			} else {
 				t.Error(err)
@@ -309,7 +345,7 @@ func TestReservationServiceDelete(t *testing.T) {
 
 		err = service.DeleteReservation(ctx, reservation)
 		assert.NoError(t, err)
-		roomReservations, err := service.GetByRoomID(ctx, reservation.RoomID)
+		roomReservations, err := service.GetByRoomID(ctx, reservation.RoomID, models.ReservationFilter{})
 		assert.NoError(t, err)
 		assert.Empty(t, roomReservations.Reservations)
 	})
@@ -354,7 +390,7 @@ func TestReservationServiceGetByRoomID(t *testing.T) {
 		err = service.Create(ctx, reservation)
 		assert.NoError(t, err)
 
-		roomReservations, err := service.GetByRoomID(ctx, roomID)
+		roomReservations, err := service.GetByRoomID(ctx, roomID, models.ReservationFilter{})
 		assert.NoError(t, err)
 		assert.NotEmpty(t, roomReservations.Reservations)
 		assert.Equal(t, roomID, roomReservations.RoomID)
@@ -368,15 +404,1084 @@ func TestReservationServiceGetByRoomID(t *testing.T) {
 		assert.Equal(t, expectedEndTime, actualEndTime)
 	})
 
+	t.Run("pagination with cursor", func(t *testing.T) {
+		_, err := db.Exec(ctx, "DELETE FROM reservations")
+		require.NoError(t, err)
+
+		roomID := "425"
+		startTime := time.Now().Add(1 * time.Hour).Truncate(time.Minute)
+		for i := 0; i < 3; i++ {
+			err := service.Create(ctx, &models.Reservation{
+				RoomID:    roomID,
+				StartTime: startTime.Add(time.Duration(i) * time.Hour),
+				EndTime:   startTime.Add(time.Duration(i)*time.Hour + 30*time.Minute),
+			})
+			require.NoError(t, err)
+		}
+
+		firstPage, err := service.GetByRoomID(ctx, roomID, models.ReservationFilter{Limit: 2})
+		require.NoError(t, err)
+		assert.Len(t, firstPage.Reservations, 2)
+		require.NotEmpty(t, firstPage.NextCursor)
+		assert.True(t, firstPage.Reservations[0].StartTime.Before(firstPage.Reservations[1].StartTime))
+
+		cursor, err := models.ParseCursor(firstPage.NextCursor)
+		require.NoError(t, err)
+
+		secondPage, err := service.GetByRoomID(ctx, roomID, models.ReservationFilter{Limit: 2, After: cursor})
+		require.NoError(t, err)
+		assert.Len(t, secondPage.Reservations, 1)
+		assert.Empty(t, secondPage.NextCursor)
+	})
+
 	t.Run("get by room ID with no reservations", func(t *testing.T) {
 		_, err := db.Exec(ctx, "DELETE FROM reservations")
 		require.NoError(t, err)
 
 		roomID := "422"
 
-		roomReservations, err := service.GetByRoomID(ctx, roomID)
+		roomReservations, err := service.GetByRoomID(ctx, roomID, models.ReservationFilter{})
 		assert.NoError(t, err)
 		assert.Empty(t, roomReservations.Reservations)
 		assert.Equal(t, roomID, roomReservations.RoomID)
 	})
 }
+
+func TestReservationServiceUpdate(t *testing.T) {
+	ctx := context.Background()
+
+	cfg := config.LoadTestConfig()
+
+	db := postgresql.NewPool(cfg)
+	defer db.Close()
+	storage := postgresql.NewStorage(db)
+	service := services.NewReservationService(storage, 2*time.Second)
+
+	t.Run("update bumps version", func(t *testing.T) {
+		_, err := db.Exec(ctx, "DELETE FROM reservations")
+		require.NoError(t, err)
+
+		reservation := &models.Reservation{
+			RoomID:    "423",
+			StartTime: time.Now().Add(1 * time.Hour),
+			EndTime:   time.Now().Add(2 * time.Hour),
+		}
+		require.NoError(t, service.Create(ctx, reservation))
+		assert.Equal(t, 1, reservation.Version)
+
+		reservation.EndTime = reservation.EndTime.Add(30 * time.Minute)
+		err = service.Update(ctx, reservation)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, reservation.Version)
+	})
+
+	t.Run("stale version is rejected", func(t *testing.T) {
+		_, err := db.Exec(ctx, "DELETE FROM reservations")
+		require.NoError(t, err)
+
+		reservation := &models.Reservation{
+			RoomID:    "424",
+			StartTime: time.Now().Add(1 * time.Hour),
+			EndTime:   time.Now().Add(2 * time.Hour),
+		}
+		require.NoError(t, service.Create(ctx, reservation))
+
+		first := *reservation
+		require.NoError(t, service.Update(ctx, &first))
+
+		stale := *reservation
+		err = service.Update(ctx, &stale)
+		assert.ErrorIs(t, err, models.ErrVersionMismatch)
+
+		err = service.DeleteByID(ctx, reservation)
+		assert.ErrorIs(t, err, models.ErrVersionMismatch)
+	})
+}
+
+func TestReservationServiceImport(t *testing.T) {
+	ctx := context.Background()
+
+	cfg := config.LoadTestConfig()
+
+	db := postgresql.NewPool(cfg)
+	defer db.Close()
+	storage := postgresql.NewStorage(db)
+	service := services.NewReservationService(storage, 2*time.Second)
+
+	startTime := time.Now().Add(1 * time.Hour).Truncate(time.Minute)
+	rows := []models.ImportRow{
+		{Line: 2, Reservation: models.Reservation{RoomID: "426", StartTime: startTime, EndTime: startTime.Add(time.Hour)}},
+		{Line: 3, Reservation: models.Reservation{RoomID: "426", StartTime: startTime.Add(30 * time.Minute), EndTime: startTime.Add(2 * time.Hour)}},
+		{Line: 4, Reservation: models.Reservation{RoomID: "426", StartTime: startTime.Add(-2 * time.Hour), EndTime: startTime}},
+	}
+
+	t.Run("dry run reports without writing", func(t *testing.T) {
+		_, err := db.Exec(ctx, "DELETE FROM reservations")
+		require.NoError(t, err)
+
+		report, err := service.Import(ctx, rows, true)
+		require.NoError(t, err)
+		assert.Equal(t, 1, report.Accepted)
+		assert.Equal(t, 1, report.Conflicts)
+		assert.Equal(t, 1, report.Invalid)
+		assert.Equal(t, models.ImportConflict, report.Results[1].Status)
+		assert.Equal(t, "PAST_TIME", report.Results[2].Code)
+
+		roomReservations, err := service.GetByRoomID(ctx, "426", models.ReservationFilter{})
+		require.NoError(t, err)
+		assert.Empty(t, roomReservations.Reservations)
+	})
+
+	t.Run("import writes accepted rows", func(t *testing.T) {
+		_, err := db.Exec(ctx, "DELETE FROM reservations")
+		require.NoError(t, err)
+
+		report, err := service.Import(ctx, rows, false)
+		require.NoError(t, err)
+		assert.Equal(t, 1, report.Accepted)
+
+		roomReservations, err := service.GetByRoomID(ctx, "426", models.ReservationFilter{})
+		require.NoError(t, err)
+		assert.Len(t, roomReservations.Reservations, 1)
+	})
+}
+
+func TestReservationServiceGetFreeBusy(t *testing.T) {
+	ctx := context.Background()
+
+	cfg := config.LoadTestConfig()
+
+	db := postgresql.NewPool(cfg)
+	defer db.Close()
+	storage := postgresql.NewStorage(db)
+	service := services.NewReservationService(storage, 2*time.Second)
+
+	_, err := db.Exec(ctx, "DELETE FROM reservations")
+	require.NoError(t, err)
+
+	base := time.Now().Add(24 * time.Hour).Truncate(time.Hour)
+	for _, reservation := range []*models.Reservation{
+		{RoomID: "411", StartTime: base, EndTime: base.Add(time.Hour)},
+		{RoomID: "411", StartTime: base.Add(time.Hour), EndTime: base.Add(2 * time.Hour)},
+		{RoomID: "411", StartTime: base.Add(4 * time.Hour), EndTime: base.Add(5 * time.Hour)},
+		{RoomID: "412", StartTime: base.Add(-time.Hour), EndTime: base.Add(time.Hour)},
+	} {
+		require.NoError(t, service.Create(ctx, reservation))
+	}
+
+	t.Run("merges and clips busy intervals", func(t *testing.T) {
+		freeBusy, err := service.GetFreeBusy(ctx, models.FreeBusyQuery{
+			RoomIDs:   []string{"412", "411", "413"},
+			StartTime: base,
+			EndTime:   base.Add(8 * time.Hour),
+		})
+		require.NoError(t, err)
+		require.Len(t, freeBusy.Rooms, 3)
+
+		assert.Equal(t, "412", freeBusy.Rooms[0].RoomID)
+		require.Len(t, freeBusy.Rooms[0].Busy, 1)
+		assert.True(t, freeBusy.Rooms[0].Busy[0].StartTime.Equal(base))
+
+		assert.Equal(t, "411", freeBusy.Rooms[1].RoomID)
+		require.Len(t, freeBusy.Rooms[1].Busy, 2)
+		assert.True(t, freeBusy.Rooms[1].Busy[0].EndTime.Equal(base.Add(2*time.Hour)))
+
+		assert.Empty(t, freeBusy.Rooms[2].Busy)
+	})
+
+	t.Run("rejects an empty room list", func(t *testing.T) {
+		_, err := service.GetFreeBusy(ctx, models.FreeBusyQuery{StartTime: base, EndTime: base.Add(time.Hour)})
+		assert.ErrorIs(t, err, models.ErrInvalidParameter)
+	})
+}
+
+func TestReservationServiceWebhooks(t *testing.T) {
+	ctx := context.Background()
+
+	cfg := config.LoadTestConfig()
+
+	db := postgresql.NewPool(cfg)
+	defer db.Close()
+	webhookService := services.NewWebhookService(postgresql.NewWebhookStorage(db))
+	service := services.NewReservationService(postgresql.NewStorage(db), 2*time.Second)
+	relay := services.NewOutboxRelay(postgresql.NewOutboxStorage(db), webhookService)
+
+	_, err := db.Exec(ctx, "DELETE FROM reservations")
+	require.NoError(t, err)
+	_, err = db.Exec(ctx, "DELETE FROM webhooks")
+	require.NoError(t, err)
+	_, err = db.Exec(ctx, "DELETE FROM outbox")
+	require.NoError(t, err)
+
+	all := &models.Webhook{URL: "http://example.com/all"}
+	require.NoError(t, webhookService.CreateWebhook(ctx, all))
+	assert.NotEmpty(t, all.Secret)
+	cancellations := &models.Webhook{
+		URL:     "http://example.com/cancelled",
+		Events:  []string{models.EventReservationCancelled},
+		RoomIDs: []string{"411"},
+	}
+	require.NoError(t, webhookService.CreateWebhook(ctx, cancellations))
+
+	reservation := &models.Reservation{
+		RoomID:    "411",
+		StartTime: time.Now().Add(1 * time.Hour),
+		EndTime:   time.Now().Add(2 * time.Hour),
+	}
+	require.NoError(t, service.Create(ctx, reservation))
+	require.NoError(t, service.DeleteByID(ctx, reservation))
+	require.NoError(t, relay.RunOnce(ctx))
+
+	deliveries, err := webhookService.ListDeliveries(ctx, all.ID, 0)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 2)
+	assert.Equal(t, models.EventReservationCancelled, deliveries[0].EventType)
+	assert.Equal(t, models.EventReservationCreated, deliveries[1].EventType)
+
+	deliveries, err = webhookService.ListDeliveries(ctx, cancellations.ID, 0)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, models.DeliveryPending, deliveries[0].Status)
+
+	err = webhookService.CreateWebhook(ctx, &models.Webhook{URL: "ftp://example.com"})
+	assert.ErrorIs(t, err, models.ErrInvalidParameter)
+}
+
+func TestReservationServiceOutbox(t *testing.T) {
+	ctx := context.Background()
+
+	cfg := config.LoadTestConfig()
+
+	db := postgresql.NewPool(cfg)
+	defer db.Close()
+	service := services.NewReservationService(postgresql.NewStorage(db), 2*time.Second)
+
+	_, err := db.Exec(ctx, "DELETE FROM reservations")
+	require.NoError(t, err)
+	_, err = db.Exec(ctx, "DELETE FROM outbox")
+	require.NoError(t, err)
+
+	reservation := &models.Reservation{
+		RoomID:    "411",
+		StartTime: time.Now().Add(1 * time.Hour),
+		EndTime:   time.Now().Add(2 * time.Hour),
+	}
+	require.NoError(t, service.Create(ctx, reservation))
+
+	stale := *reservation
+	stale.Version = reservation.Version + 1
+	stale.EndTime = reservation.EndTime.Add(time.Hour)
+	assert.ErrorIs(t, service.Update(ctx, &stale), models.ErrVersionMismatch)
+
+	var events []models.ReservationEvent
+	relay := services.NewOutboxRelay(postgresql.NewOutboxStorage(db), publisherFunc(func(event models.ReservationEvent) error {
+		events = append(events, event)
+		return nil
+	}))
+	require.NoError(t, relay.RunOnce(ctx))
+	require.NoError(t, relay.RunOnce(ctx))
+
+	require.Len(t, events, 1, "rejected writes leave no event and published events are not sent again")
+	assert.Equal(t, models.EventReservationCreated, events[0].Type)
+	assert.Equal(t, reservation.ID, events[0].Reservation.ID)
+	assert.NotZero(t, events[0].ID)
+
+	// a room whose sink keeps failing does not hold back other rooms, even
+	// with more pending events than fit in a batch
+	for i := 0; i < 3; i++ {
+		start := time.Now().Add(time.Duration(3+i) * time.Hour)
+		require.NoError(t, service.Create(ctx, &models.Reservation{RoomID: "412", StartTime: start, EndTime: start.Add(time.Hour)}))
+	}
+	require.NoError(t, service.Create(ctx, &models.Reservation{RoomID: "413", StartTime: reservation.StartTime, EndTime: reservation.EndTime}))
+
+	var sent []string
+	failing := services.NewOutboxRelay(postgresql.NewOutboxStorage(db), publisherFunc(func(event models.ReservationEvent) error {
+		if event.Reservation.RoomID == "412" {
+			return errors.New("sink is down")
+		}
+		sent = append(sent, event.Reservation.RoomID)
+		return nil
+	}))
+	failing.BatchSize = 2
+	require.NoError(t, failing.RunOnce(ctx))
+	require.NoError(t, failing.RunOnce(ctx))
+	assert.Equal(t, []string{"413"}, sent)
+}
+
+type publisherFunc func(event models.ReservationEvent) error
+
+func (f publisherFunc) Publish(_ context.Context, event models.ReservationEvent) error {
+	return f(event)
+}
+
+func TestReservationServiceChanges(t *testing.T) {
+	ctx := context.Background()
+
+	cfg := config.LoadTestConfig()
+
+	db := postgresql.NewPool(cfg)
+	defer db.Close()
+	storage := postgresql.NewStorage(db)
+	service := services.NewReservationService(storage, 2*time.Second)
+
+	start, err := service.GetChanges(ctx, 0, 1)
+	require.NoError(t, err)
+	for start.HasMore {
+		start, err = service.GetChanges(ctx, start.NextSince, 500)
+		require.NoError(t, err)
+	}
+	since := start.NextSince
+
+	base := time.Now().Add(24 * time.Hour).Truncate(time.Hour)
+	first := &models.Reservation{RoomID: "421", StartTime: base, EndTime: base.Add(time.Hour)}
+	second := &models.Reservation{RoomID: "422", StartTime: base, EndTime: base.Add(time.Hour)}
+	require.NoError(t, service.Create(ctx, first))
+	require.NoError(t, service.Create(ctx, second))
+
+	first.StartTime = base.Add(time.Hour)
+	first.EndTime = base.Add(2 * time.Hour)
+	require.NoError(t, service.Update(ctx, first))
+	require.NoError(t, service.DeleteByID(ctx, &models.Reservation{ID: second.ID, RoomID: "422"}))
+
+	feed, err := service.GetChanges(ctx, since, 1)
+	require.NoError(t, err)
+	require.Len(t, feed.Changes, 1)
+	assert.True(t, feed.HasMore)
+
+	feed, err = service.GetChanges(ctx, feed.NextSince, 10)
+	require.NoError(t, err)
+	require.Len(t, feed.Changes, 1)
+	assert.False(t, feed.HasMore)
+
+	// the second reservation was written last, as a tombstone
+	assert.Equal(t, second.ID, feed.Changes[0].Reservation.ID)
+	assert.True(t, feed.Changes[0].Deleted)
+
+	_, err = service.GetChanges(ctx, -1, 10)
+	assert.ErrorIs(t, err, models.ErrInvalidParameter)
+}
+
+func TestReservationServiceWaitlist(t *testing.T) {
+	ctx := context.Background()
+
+	cfg := config.LoadTestConfig()
+
+	db := postgresql.NewPool(cfg)
+	defer db.Close()
+	storage := postgresql.NewStorage(db)
+	service := services.NewReservationService(storage, 2*time.Second)
+
+	_, err := db.Exec(ctx, "DELETE FROM waitlist")
+	require.NoError(t, err)
+	_, err = db.Exec(ctx, "DELETE FROM reservations")
+	require.NoError(t, err)
+
+	base := time.Now().Add(24 * time.Hour).Truncate(time.Hour)
+	booked := &models.Reservation{RoomID: "431", UserID: "carol", StartTime: base, EndTime: base.Add(time.Hour)}
+	require.NoError(t, service.Create(ctx, booked))
+
+	t.Run("free slots are booked directly", func(t *testing.T) {
+		err := service.JoinWaitlist(ctx, &models.WaitlistEntry{RoomID: "431", UserID: "alice", StartTime: base.Add(2 * time.Hour), EndTime: base.Add(3 * time.Hour)})
+		assert.ErrorIs(t, err, models.ErrSlotAvailable)
+	})
+
+	offered := &models.WaitlistEntry{RoomID: "431", UserID: "alice", StartTime: base, EndTime: base.Add(time.Hour)}
+	require.NoError(t, service.JoinWaitlist(ctx, offered))
+	autoBooked := &models.WaitlistEntry{RoomID: "431", UserID: "bob", StartTime: base, EndTime: base.Add(30 * time.Minute), AutoBook: true}
+	require.NoError(t, service.JoinWaitlist(ctx, autoBooked))
+	assert.Equal(t, models.WaitlistWaiting, offered.Status)
+
+	require.NoError(t, service.DeleteByID(ctx, &models.Reservation{ID: booked.ID, RoomID: "431"}))
+
+	t.Run("the first user is offered the slot and it is held", func(t *testing.T) {
+		entry, err := service.GetWaitlistEntry(ctx, offered.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.WaitlistOffered, entry.Status)
+		require.NotNil(t, entry.OfferExpiresAt)
+
+		entry, err = service.GetWaitlistEntry(ctx, autoBooked.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.WaitlistWaiting, entry.Status)
+
+		err = service.Create(ctx, &models.Reservation{RoomID: "431", StartTime: base, EndTime: base.Add(time.Hour)})
+		assert.ErrorIs(t, err, models.ErrRoomAlreadyReservated)
+	})
+
+	t.Run("a declined offer passes to the next user", func(t *testing.T) {
+		require.NoError(t, service.LeaveWaitlist(ctx, offered.ID))
+
+		entry, err := service.GetWaitlistEntry(ctx, autoBooked.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.WaitlistBooked, entry.Status)
+
+		reservation, err := service.GetByID(ctx, entry.ReservationID)
+		require.NoError(t, err)
+		assert.Equal(t, "bob", reservation.UserID)
+
+		_, err = service.ClaimWaitlistOffer(ctx, offered.ID)
+		assert.ErrorIs(t, err, models.ErrNoWaitlistOffer)
+	})
+}
+
+func TestReservationServiceHolds(t *testing.T) {
+	ctx := context.Background()
+
+	cfg := config.LoadTestConfig()
+
+	db := postgresql.NewPool(cfg)
+	defer db.Close()
+	storage := postgresql.NewStorage(db)
+	service := services.NewReservationService(storage, 2*time.Second)
+
+	_, err := db.Exec(ctx, "DELETE FROM holds")
+	require.NoError(t, err)
+	_, err = db.Exec(ctx, "DELETE FROM reservations")
+	require.NoError(t, err)
+
+	base := time.Now().Add(24 * time.Hour).Truncate(time.Hour)
+
+	t.Run("a hold blocks the slot until confirmed", func(t *testing.T) {
+		hold := &models.Hold{RoomID: "441", UserID: "alice", StartTime: base, EndTime: base.Add(time.Hour)}
+		require.NoError(t, service.CreateHold(ctx, hold))
+		assert.Equal(t, models.HoldActive, hold.Status)
+		assert.Equal(t, 300, hold.TTLSeconds)
+
+		err := service.Create(ctx, &models.Reservation{RoomID: "441", StartTime: base.Add(30 * time.Minute), EndTime: base.Add(2 * time.Hour)})
+		assert.ErrorIs(t, err, models.ErrRoomAlreadyReservated)
+		err = service.CreateHold(ctx, &models.Hold{RoomID: "441", StartTime: base, EndTime: base.Add(time.Hour)})
+		assert.ErrorIs(t, err, models.ErrRoomAlreadyReservated)
+
+		reservation, err := service.ConfirmHold(ctx, hold.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "alice", reservation.UserID)
+
+		_, err = service.ConfirmHold(ctx, hold.ID)
+		assert.ErrorIs(t, err, models.ErrHoldNotActive)
+	})
+
+	t.Run("a started hold cannot be confirmed", func(t *testing.T) {
+		hold := &models.Hold{RoomID: "448", StartTime: base, EndTime: base.Add(time.Hour)}
+		require.NoError(t, service.CreateHold(ctx, hold))
+		_, err := db.Exec(ctx, "UPDATE holds SET start_time = $2 WHERE id = $1", hold.ID, time.Now().Add(-time.Minute))
+		require.NoError(t, err)
+
+		_, err = service.ConfirmHold(ctx, hold.ID)
+		assert.ErrorIs(t, err, models.ErrPastTime)
+	})
+
+	t.Run("expired holds stop counting", func(t *testing.T) {
+		hold := &models.Hold{RoomID: "442", StartTime: base, EndTime: base.Add(time.Hour), TTLSeconds: 1}
+		require.NoError(t, service.CreateHold(ctx, hold))
+		_, err := db.Exec(ctx, "UPDATE holds SET expires_at = $2 WHERE id = $1", hold.ID, time.Now().Add(-time.Second))
+		require.NoError(t, err)
+
+		require.NoError(t, service.ExpireHolds(ctx))
+		expired, err := service.GetHold(ctx, hold.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.HoldExpired, expired.Status)
+
+		require.NoError(t, service.Create(ctx, &models.Reservation{RoomID: "442", StartTime: base, EndTime: base.Add(time.Hour)}))
+	})
+
+	t.Run("suggested slots avoid holds", func(t *testing.T) {
+		require.NoError(t, service.CreateHold(ctx, &models.Hold{RoomID: "445", StartTime: base, EndTime: base.Add(time.Hour)}))
+		require.NoError(t, service.Create(ctx, &models.Reservation{RoomID: "445", StartTime: base.Add(time.Hour), EndTime: base.Add(2 * time.Hour)}))
+
+		err := service.Create(ctx, &models.Reservation{RoomID: "445", StartTime: base.Add(30 * time.Minute), EndTime: base.Add(90 * time.Minute)})
+		var conflictErr *models.ConflictError
+		require.ErrorAs(t, err, &conflictErr)
+		require.NotEmpty(t, conflictErr.SuggestedSlots)
+		for _, slot := range conflictErr.SuggestedSlots {
+			assert.False(t, slot.StartTime.Before(base.Add(time.Hour)) && slot.EndTime.After(base), "slot %v overlaps the hold", slot)
+		}
+	})
+
+	t.Run("held rooms are not suggested", func(t *testing.T) {
+		require.NoError(t, service.Create(ctx, &models.Reservation{RoomID: "446", StartTime: base.Add(3 * time.Hour), EndTime: base.Add(4 * time.Hour)}))
+		require.NoError(t, service.Create(ctx, &models.Reservation{RoomID: "447", StartTime: base.Add(5 * time.Hour), EndTime: base.Add(6 * time.Hour)}))
+		require.NoError(t, service.CreateHold(ctx, &models.Hold{RoomID: "447", StartTime: base.Add(3 * time.Hour), EndTime: base.Add(4 * time.Hour)}))
+
+		err := service.Create(ctx, &models.Reservation{RoomID: "446", StartTime: base.Add(3 * time.Hour), EndTime: base.Add(4 * time.Hour)})
+		var conflictErr *models.ConflictError
+		require.ErrorAs(t, err, &conflictErr)
+		assert.NotContains(t, conflictErr.SuggestedRooms, "447")
+	})
+
+	t.Run("imports do not book over a hold", func(t *testing.T) {
+		require.NoError(t, service.CreateHold(ctx, &models.Hold{RoomID: "444", StartTime: base, EndTime: base.Add(time.Hour)}))
+
+		report, err := service.Import(ctx, []models.ImportRow{
+			{Line: 2, Reservation: models.Reservation{RoomID: "444", StartTime: base, EndTime: base.Add(time.Hour)}},
+		}, false)
+		require.NoError(t, err)
+		assert.Equal(t, 1, report.Conflicts)
+	})
+
+	t.Run("ttl is bounded", func(t *testing.T) {
+		err := service.CreateHold(ctx, &models.Hold{RoomID: "443", StartTime: base, EndTime: base.Add(time.Hour), TTLSeconds: 3600})
+		assert.ErrorIs(t, err, models.ErrInvalidParameter)
+	})
+}
+
+func TestReservationServiceApproval(t *testing.T) {
+	ctx := context.Background()
+
+	cfg := config.LoadTestConfig()
+
+	db := postgresql.NewPool(cfg)
+	defer db.Close()
+	storage := postgresql.NewStorage(db)
+	service := services.NewReservationService(storage, 2*time.Second)
+
+	_, err := db.Exec(ctx, "DELETE FROM reservations")
+	require.NoError(t, err)
+	_, err = db.Exec(ctx, "DELETE FROM rooms")
+	require.NoError(t, err)
+
+	base := time.Now().Add(24 * time.Hour).Truncate(time.Hour)
+
+	t.Run("pending requests block the slot by default", func(t *testing.T) {
+		require.NoError(t, service.UpdateRoom(ctx, &models.Room{ID: "aud-1", RequiresApproval: true, PendingBlocks: true}))
+
+		reservation := &models.Reservation{RoomID: "aud-1", UserID: "alice", StartTime: base, EndTime: base.Add(time.Hour)}
+		require.NoError(t, service.Create(ctx, reservation))
+		assert.Equal(t, models.StatusPending, reservation.Status)
+
+		err := service.Create(ctx, &models.Reservation{RoomID: "aud-1", StartTime: base, EndTime: base.Add(time.Hour)})
+		assert.ErrorIs(t, err, models.ErrRoomAlreadyReservated)
+
+		rejected, err := service.RejectReservation(ctx, reservation.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.StatusRejected, rejected.Status)
+		assert.NotNil(t, rejected.CancelledAt)
+
+		_, err = service.ApproveReservation(ctx, reservation.ID)
+		assert.ErrorIs(t, err, models.ErrNoMatchingReservation)
+		require.NoError(t, service.Create(ctx, &models.Reservation{RoomID: "aud-1", StartTime: base, EndTime: base.Add(time.Hour)}))
+	})
+
+	t.Run("approving one of overlapping requests rejects the others", func(t *testing.T) {
+		require.NoError(t, service.UpdateRoom(ctx, &models.Room{ID: "aud-2", RequiresApproval: true, PendingBlocks: false}))
+
+		first := &models.Reservation{RoomID: "aud-2", UserID: "alice", StartTime: base, EndTime: base.Add(time.Hour)}
+		second := &models.Reservation{RoomID: "aud-2", UserID: "bob", StartTime: base.Add(30 * time.Minute), EndTime: base.Add(2 * time.Hour)}
+		require.NoError(t, service.Create(ctx, first))
+		require.NoError(t, service.Create(ctx, second))
+
+		pending, err := service.ListPending(ctx, "aud-2")
+		require.NoError(t, err)
+		assert.Len(t, pending, 2)
+
+		approved, err := service.ApproveReservation(ctx, second.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.StatusApproved, approved.Status)
+
+		_, err = service.GetByID(ctx, first.ID)
+		assert.ErrorIs(t, err, models.ErrNoMatchingReservation)
+		pending, err = service.ListPending(ctx, "aud-2")
+		require.NoError(t, err)
+		assert.Empty(t, pending)
+
+		_, err = service.ApproveReservation(ctx, second.ID)
+		assert.ErrorIs(t, err, models.ErrReservationNotPending)
+	})
+
+	t.Run("imported rows wait for approval", func(t *testing.T) {
+		require.NoError(t, service.UpdateRoom(ctx, &models.Room{ID: "aud-3", RequiresApproval: true, PendingBlocks: true}))
+
+		report, err := service.Import(ctx, []models.ImportRow{
+			{Line: 2, Reservation: models.Reservation{RoomID: "aud-3", StartTime: base, EndTime: base.Add(time.Hour)}},
+		}, false)
+		require.NoError(t, err)
+		require.Equal(t, 1, report.Accepted)
+		assert.Equal(t, models.StatusPending, report.Results[0].Reservation.Status)
+
+		pending, err := service.ListPending(ctx, "aud-3")
+		require.NoError(t, err)
+		assert.Len(t, pending, 1)
+	})
+
+	t.Run("rooms without settings approve right away", func(t *testing.T) {
+		reservation := &models.Reservation{RoomID: "451", StartTime: base, EndTime: base.Add(time.Hour)}
+		require.NoError(t, service.Create(ctx, reservation))
+		assert.Equal(t, models.StatusApproved, reservation.Status)
+	})
+}
+
+func TestReservationServiceCheckIn(t *testing.T) {
+	ctx := context.Background()
+
+	cfg := config.LoadTestConfig()
+
+	db := postgresql.NewPool(cfg)
+	defer db.Close()
+	storage := postgresql.NewStorage(db)
+	service := services.NewReservationService(storage, 2*time.Second)
+
+	_, err := db.Exec(ctx, "DELETE FROM reservations")
+	require.NoError(t, err)
+
+	t.Run("check-in opens shortly before the start", func(t *testing.T) {
+		soon := time.Now().Add(5 * time.Minute).Truncate(time.Second)
+		reservation := &models.Reservation{RoomID: "461", StartTime: soon, EndTime: soon.Add(time.Hour)}
+		require.NoError(t, service.Create(ctx, reservation))
+
+		checkedIn, err := service.CheckIn(ctx, reservation.ID)
+		require.NoError(t, err)
+		require.NotNil(t, checkedIn.CheckedInAt)
+
+		again, err := service.CheckIn(ctx, reservation.ID)
+		require.NoError(t, err)
+		assert.Equal(t, checkedIn.Version, again.Version)
+
+		later := soon.Add(2 * time.Hour)
+		early := &models.Reservation{RoomID: "461", StartTime: later, EndTime: later.Add(time.Hour)}
+		require.NoError(t, service.Create(ctx, early))
+		_, err = service.CheckIn(ctx, early.ID)
+		assert.ErrorIs(t, err, models.ErrCheckInNotOpen)
+	})
+
+	t.Run("no-shows are released after the grace period", func(t *testing.T) {
+		start := time.Now().Add(time.Hour).Truncate(time.Second)
+		noShow := &models.Reservation{RoomID: "462", StartTime: start, EndTime: start.Add(time.Hour)}
+		present := &models.Reservation{RoomID: "463", StartTime: start, EndTime: start.Add(time.Hour)}
+		require.NoError(t, service.Create(ctx, noShow))
+		require.NoError(t, service.Create(ctx, present))
+
+		started := time.Now().Add(-20 * time.Minute)
+		_, err := db.Exec(ctx, "UPDATE reservations SET start_time = $1 WHERE id = ANY($2)", started, []int{noShow.ID, present.ID})
+		require.NoError(t, err)
+		_, err = service.CheckIn(ctx, present.ID)
+		require.NoError(t, err)
+
+		require.NoError(t, service.ReleaseNoShows(ctx, 10*time.Minute))
+
+		_, err = service.GetByID(ctx, noShow.ID)
+		assert.ErrorIs(t, err, models.ErrNoMatchingReservation)
+		_, err = service.GetByID(ctx, present.ID)
+		assert.NoError(t, err)
+
+		isReserved, err := storage.IsReserved(ctx, "462", time.Now().Add(time.Minute), start.Add(time.Hour))
+		require.NoError(t, err)
+		assert.False(t, isReserved)
+
+		var flagged bool
+		require.NoError(t, db.QueryRow(ctx, "SELECT no_show FROM reservations WHERE id = $1", noShow.ID).Scan(&flagged))
+		assert.True(t, flagged)
+	})
+}
+
+func TestReservationServiceEndExtend(t *testing.T) {
+	ctx := context.Background()
+
+	cfg := config.LoadTestConfig()
+
+	db := postgresql.NewPool(cfg)
+	defer db.Close()
+	storage := postgresql.NewStorage(db)
+	service := services.NewReservationService(storage, 2*time.Second)
+
+	_, err := db.Exec(ctx, "DELETE FROM reservations")
+	require.NoError(t, err)
+
+	base := time.Now().Add(24 * time.Hour).Truncate(time.Hour)
+
+	t.Run("extension only into a free slot", func(t *testing.T) {
+		reservation := &models.Reservation{RoomID: "471", StartTime: base, EndTime: base.Add(time.Hour)}
+		next := &models.Reservation{RoomID: "471", StartTime: base.Add(90 * time.Minute), EndTime: base.Add(2 * time.Hour)}
+		require.NoError(t, service.Create(ctx, reservation))
+		require.NoError(t, service.Create(ctx, next))
+
+		started := time.Now().Add(-10 * time.Minute).Truncate(time.Second)
+		for _, r := range []*models.Reservation{reservation, next} {
+			_, err := db.Exec(ctx, "UPDATE reservations SET start_time = $2, end_time = $3 WHERE id = $1",
+				r.ID, started.Add(r.StartTime.Sub(base)), started.Add(r.EndTime.Sub(base)))
+			require.NoError(t, err)
+		}
+
+		extended, err := service.ExtendReservation(ctx, reservation.ID, 0)
+		require.NoError(t, err)
+		assert.True(t, extended.EndTime.Equal(started.Add(75*time.Minute)))
+
+		_, err = service.ExtendReservation(ctx, reservation.ID, 30*time.Minute)
+		assert.ErrorIs(t, err, models.ErrRoomAlreadyReservated)
+	})
+
+	t.Run("only a meeting in progress is extended", func(t *testing.T) {
+		reservation := &models.Reservation{RoomID: "473", StartTime: base, EndTime: base.Add(time.Hour)}
+		require.NoError(t, service.Create(ctx, reservation))
+
+		_, err := service.ExtendReservation(ctx, reservation.ID, 0)
+		assert.ErrorIs(t, err, models.ErrReservationNotInProgress)
+
+		started := time.Now().Add(-10 * time.Minute)
+		_, err = db.Exec(ctx, "UPDATE reservations SET start_time = $2, end_time = $3, status = $4 WHERE id = $1",
+			reservation.ID, started, started.Add(time.Hour), models.StatusPending)
+		require.NoError(t, err)
+
+		_, err = service.ExtendReservation(ctx, reservation.ID, 0)
+		assert.ErrorIs(t, err, models.ErrReservationNotInProgress)
+	})
+
+	t.Run("ending early frees the rest of the slot", func(t *testing.T) {
+		reservation := &models.Reservation{RoomID: "472", StartTime: base, EndTime: base.Add(time.Hour)}
+		require.NoError(t, service.Create(ctx, reservation))
+
+		_, err := service.EndReservation(ctx, reservation.ID)
+		assert.ErrorIs(t, err, models.ErrReservationNotInProgress)
+
+		started := time.Now().Add(-10 * time.Minute)
+		_, err = db.Exec(ctx, "UPDATE reservations SET start_time = $2, end_time = $3 WHERE id = $1", reservation.ID, started, started.Add(time.Hour))
+		require.NoError(t, err)
+
+		ended, err := service.EndReservation(ctx, reservation.ID)
+		require.NoError(t, err)
+		assert.False(t, ended.EndTime.After(time.Now()))
+
+		isReserved, err := storage.IsReserved(ctx, "472", time.Now().Add(time.Minute), started.Add(time.Hour))
+		require.NoError(t, err)
+		assert.False(t, isReserved)
+	})
+}
+
+func TestReservationServiceKiosk(t *testing.T) {
+	ctx := context.Background()
+
+	cfg := config.LoadTestConfig()
+
+	db := postgresql.NewPool(cfg)
+	defer db.Close()
+	storage := postgresql.NewStorage(db)
+	service := services.NewReservationService(storage, 2*time.Second)
+
+	_, err := db.Exec(ctx, "DELETE FROM reservations")
+	require.NoError(t, err)
+
+	next := time.Now().Add(2 * time.Hour).Truncate(time.Hour)
+	require.NoError(t, service.Create(ctx, &models.Reservation{RoomID: "481", StartTime: next, EndTime: next.Add(time.Hour)}))
+
+	status, err := service.GetRoomStatus(ctx, "481")
+	require.NoError(t, err)
+	assert.False(t, status.Occupied)
+	require.NotNil(t, status.FreeUntil)
+	assert.True(t, status.FreeUntil.Equal(next))
+
+	reservation, err := service.QuickBook(ctx, "481", "device:481", 20*time.Minute)
+	require.NoError(t, err)
+	assert.NotNil(t, reservation.CheckedInAt)
+
+	stored, err := service.GetByID(ctx, reservation.ID)
+	require.NoError(t, err)
+	assert.NotNil(t, stored.CheckedInAt)
+
+	_, err = service.QuickBook(ctx, "481", "device:481", 0)
+	assert.ErrorIs(t, err, models.ErrRoomAlreadyReservated)
+
+	status, err = service.GetRoomStatus(ctx, "481")
+	require.NoError(t, err)
+	assert.True(t, status.Occupied)
+	require.NotNil(t, status.Current)
+	assert.Equal(t, reservation.ID, status.Current.ID)
+	assert.Nil(t, status.FreeUntil)
+}
+
+func TestReservationServiceBatch(t *testing.T) {
+	ctx := context.Background()
+
+	cfg := config.LoadTestConfig()
+
+	db := postgresql.NewPool(cfg)
+	defer db.Close()
+	storage := postgresql.NewStorage(db)
+	service := services.NewReservationService(storage, 2*time.Second)
+
+	_, err := db.Exec(ctx, "DELETE FROM reservations")
+	require.NoError(t, err)
+
+	base := time.Now().Add(24 * time.Hour).Truncate(time.Hour)
+	item := func(roomID string, offset time.Duration) models.Reservation {
+		return models.Reservation{RoomID: roomID, StartTime: base.Add(offset), EndTime: base.Add(offset + time.Hour)}
+	}
+
+	t.Run("all rooms are booked together", func(t *testing.T) {
+		batch := []models.Reservation{item("491", 0), item("492", 0), item("493", 0)}
+		require.NoError(t, service.CreateBatch(ctx, batch))
+		for _, reservation := range batch {
+			assert.NotZero(t, reservation.ID)
+		}
+	})
+
+	t.Run("one conflict books nothing", func(t *testing.T) {
+		batch := []models.Reservation{item("494", 0), item("491", 30*time.Minute), item("494", 30*time.Minute)}
+		err := service.CreateBatch(ctx, batch)
+		assert.ErrorIs(t, err, models.ErrBatchConflict)
+
+		var batchErr *models.BatchError
+		require.ErrorAs(t, err, &batchErr)
+		assert.Equal(t, models.ImportAccepted, batchErr.Items[0].Status)
+		assert.Equal(t, models.ImportConflict, batchErr.Items[1].Status)
+		assert.NotEmpty(t, batchErr.Items[1].Conflicts)
+		assert.Equal(t, []int{0}, batchErr.Items[2].ConflictingItems)
+
+		isReserved, err := storage.IsReserved(ctx, "494", base, base.Add(time.Hour))
+		require.NoError(t, err)
+		assert.False(t, isReserved)
+	})
+}
+
+func TestReservationServiceRoomParts(t *testing.T) {
+	ctx := context.Background()
+
+	cfg := config.LoadTestConfig()
+
+	db := postgresql.NewPool(cfg)
+	defer db.Close()
+	storage := postgresql.NewStorage(db)
+	service := services.NewReservationService(storage, 2*time.Second)
+
+	_, err := db.Exec(ctx, "DELETE FROM reservations")
+	require.NoError(t, err)
+	_, err = db.Exec(ctx, "DELETE FROM rooms")
+	require.NoError(t, err)
+
+	for _, part := range []string{"hall-a", "hall-b"} {
+		require.NoError(t, service.UpdateRoom(ctx, &models.Room{ID: part, ParentID: "hall", PendingBlocks: true}))
+	}
+
+	hall, err := service.GetRoom(ctx, "hall")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"hall-a", "hall-b"}, hall.Parts)
+
+	err = service.UpdateRoom(ctx, &models.Room{ID: "hall", ParentID: "aud-9", PendingBlocks: true})
+	assert.ErrorIs(t, err, models.ErrInvalidParameter)
+
+	base := time.Now().Add(24 * time.Hour).Truncate(time.Hour)
+
+	t.Run("parts do not block each other", func(t *testing.T) {
+		require.NoError(t, service.Create(ctx, &models.Reservation{RoomID: "hall-a", StartTime: base, EndTime: base.Add(time.Hour)}))
+		require.NoError(t, service.Create(ctx, &models.Reservation{RoomID: "hall-b", StartTime: base, EndTime: base.Add(time.Hour)}))
+	})
+
+	t.Run("a part blocks the combined room", func(t *testing.T) {
+		err := service.Create(ctx, &models.Reservation{RoomID: "hall", StartTime: base.Add(30 * time.Minute), EndTime: base.Add(2 * time.Hour)})
+		assert.ErrorIs(t, err, models.ErrRoomAlreadyReservated)
+	})
+
+	t.Run("the combined room blocks its parts", func(t *testing.T) {
+		require.NoError(t, service.Create(ctx, &models.Reservation{RoomID: "hall", StartTime: base.Add(2 * time.Hour), EndTime: base.Add(3 * time.Hour)}))
+
+		isReserved, err := storage.IsReserved(ctx, "hall-b", base.Add(2*time.Hour), base.Add(3*time.Hour))
+		require.NoError(t, err)
+		assert.True(t, isReserved)
+	})
+
+	t.Run("batch items in linked rooms conflict", func(t *testing.T) {
+		batch := []models.Reservation{
+			{RoomID: "hall", StartTime: base.Add(4 * time.Hour), EndTime: base.Add(5 * time.Hour)},
+			{RoomID: "hall-a", StartTime: base.Add(4 * time.Hour), EndTime: base.Add(5 * time.Hour)},
+		}
+		err := service.CreateBatch(ctx, batch)
+		assert.ErrorIs(t, err, models.ErrBatchConflict)
+	})
+}
+
+func TestReservationServiceCapacity(t *testing.T) {
+	ctx := context.Background()
+
+	cfg := config.LoadTestConfig()
+
+	db := postgresql.NewPool(cfg)
+	defer db.Close()
+	storage := postgresql.NewStorage(db)
+	service := services.NewReservationService(storage, 2*time.Second)
+
+	_, err := db.Exec(ctx, "DELETE FROM reservations")
+	require.NoError(t, err)
+	_, err = db.Exec(ctx, "DELETE FROM rooms")
+	require.NoError(t, err)
+
+	require.NoError(t, service.UpdateRoom(ctx, &models.Room{ID: "lounge", PendingBlocks: true, Capacity: 10}))
+
+	base := time.Now().Add(24 * time.Hour).Truncate(time.Hour)
+
+	t.Run("overlapping reservations share the seats", func(t *testing.T) {
+		require.NoError(t, service.Create(ctx, &models.Reservation{RoomID: "lounge", StartTime: base, EndTime: base.Add(2 * time.Hour), Seats: 4}))
+		require.NoError(t, service.Create(ctx, &models.Reservation{RoomID: "lounge", StartTime: base.Add(time.Hour), EndTime: base.Add(3 * time.Hour), Seats: 4}))
+
+		err := service.Create(ctx, &models.Reservation{RoomID: "lounge", StartTime: base.Add(90 * time.Minute), EndTime: base.Add(4 * time.Hour), Seats: 3})
+		assert.ErrorIs(t, err, models.ErrRoomAlreadyReservated)
+
+		reservation := &models.Reservation{RoomID: "lounge", StartTime: base.Add(90 * time.Minute), EndTime: base.Add(4 * time.Hour), Seats: 2}
+		require.NoError(t, service.Create(ctx, reservation))
+		assert.Equal(t, 2, reservation.Seats)
+	})
+
+	t.Run("cancelling a shared interval needs the id", func(t *testing.T) {
+		first := &models.Reservation{RoomID: "lounge", StartTime: base.Add(14 * time.Hour), EndTime: base.Add(15 * time.Hour), Seats: 2}
+		second := &models.Reservation{RoomID: "lounge", StartTime: base.Add(14 * time.Hour), EndTime: base.Add(15 * time.Hour), Seats: 3}
+		require.NoError(t, service.Create(ctx, first))
+		require.NoError(t, service.Create(ctx, second))
+
+		err := service.DeleteReservation(ctx, &models.Reservation{RoomID: "lounge", StartTime: first.StartTime, EndTime: first.EndTime})
+		assert.ErrorIs(t, err, models.ErrAmbiguousReservation)
+
+		_, err = service.GetByID(ctx, first.ID)
+		assert.NoError(t, err)
+		_, err = service.GetByID(ctx, second.ID)
+		assert.NoError(t, err)
+
+		require.NoError(t, service.DeleteByID(ctx, second))
+		require.NoError(t, service.DeleteReservation(ctx, &models.Reservation{RoomID: "lounge", StartTime: first.StartTime, EndTime: first.EndTime}))
+		_, err = service.GetByID(ctx, first.ID)
+		assert.ErrorIs(t, err, models.ErrNoMatchingReservation)
+	})
+
+	t.Run("rejected batch items take no seats", func(t *testing.T) {
+		require.NoError(t, service.Create(ctx, &models.Reservation{RoomID: "lounge", StartTime: base.Add(16 * time.Hour), EndTime: base.Add(17 * time.Hour), Seats: 5}))
+
+		err := service.CreateBatch(ctx, []models.Reservation{
+			{RoomID: "lounge", StartTime: base.Add(16 * time.Hour), EndTime: base.Add(17 * time.Hour), Seats: 8},
+			{RoomID: "lounge", StartTime: base.Add(16 * time.Hour), EndTime: base.Add(17 * time.Hour), Seats: 4},
+		})
+		var batchErr *models.BatchError
+		require.ErrorAs(t, err, &batchErr)
+		assert.Equal(t, models.ImportConflict, batchErr.Items[0].Status)
+		assert.Equal(t, models.ImportAccepted, batchErr.Items[1].Status)
+		assert.Empty(t, batchErr.Items[1].ConflictingItems)
+	})
+
+	t.Run("seats beyond the capacity", func(t *testing.T) {
+		err := service.Create(ctx, &models.Reservation{RoomID: "lounge", StartTime: base.Add(5 * time.Hour), EndTime: base.Add(6 * time.Hour), Seats: 11})
+		assert.ErrorIs(t, err, models.ErrInvalidParameter)
+	})
+
+	t.Run("concurrent requests never overbook", func(t *testing.T) {
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		successCount := 0
+
+		wg.Add(30)
+		for i := 0; i < 30; i++ {
+			go func() {
+				defer wg.Done()
+
+				err := service.Create(ctx, &models.Reservation{RoomID: "lounge", StartTime: base.Add(7 * time.Hour), EndTime: base.Add(8 * time.Hour)})
+				if err == nil {
+					mu.Lock()
+					successCount++
+					mu.Unlock()
+				} else if !errors.Is(err, models.ErrRoomAlreadyReservated) {
+					t.Error(err)
+				}
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, 10, successCount)
+	})
+
+	t.Run("suggested slots have enough seats", func(t *testing.T) {
+		require.NoError(t, service.Create(ctx, &models.Reservation{RoomID: "lounge", StartTime: base.Add(10 * time.Hour), EndTime: base.Add(11 * time.Hour), Seats: 6}))
+		require.NoError(t, service.Create(ctx, &models.Reservation{RoomID: "lounge", StartTime: base.Add(11 * time.Hour), EndTime: base.Add(12 * time.Hour), Seats: 6}))
+
+		err := service.Create(ctx, &models.Reservation{RoomID: "lounge", StartTime: base.Add(10 * time.Hour), EndTime: base.Add(11 * time.Hour), Seats: 5})
+		var conflictErr *models.ConflictError
+		require.ErrorAs(t, err, &conflictErr)
+		require.NotEmpty(t, conflictErr.SuggestedSlots)
+		for _, slot := range conflictErr.SuggestedSlots {
+			assert.False(t, slot.StartTime.Before(base.Add(12*time.Hour)) && slot.EndTime.After(base.Add(10*time.Hour)), "slot %v lacks seats", slot)
+		}
+	})
+
+	t.Run("approval rejects only requests that no longer fit", func(t *testing.T) {
+		require.NoError(t, service.UpdateRoom(ctx, &models.Room{ID: "studio", RequiresApproval: true, PendingBlocks: false, Capacity: 10}))
+
+		first := &models.Reservation{RoomID: "studio", StartTime: base, EndTime: base.Add(time.Hour), Seats: 4}
+		second := &models.Reservation{RoomID: "studio", StartTime: base, EndTime: base.Add(time.Hour), Seats: 4}
+		third := &models.Reservation{RoomID: "studio", StartTime: base, EndTime: base.Add(time.Hour), Seats: 8}
+		for _, reservation := range []*models.Reservation{first, second, third} {
+			require.NoError(t, service.Create(ctx, reservation))
+		}
+
+		_, err := service.ApproveReservation(ctx, first.ID)
+		require.NoError(t, err)
+
+		pending, err := service.ListPending(ctx, "studio")
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+		assert.Equal(t, second.ID, pending[0].ID)
+	})
+}
+
+func TestReservationServiceResources(t *testing.T) {
+	ctx := context.Background()
+
+	cfg := config.LoadTestConfig()
+
+	db := postgresql.NewPool(cfg)
+	defer db.Close()
+	storage := postgresql.NewStorage(db)
+	service := services.NewReservationService(storage, 2*time.Second)
+
+	_, err := db.Exec(ctx, "DELETE FROM reservations")
+	require.NoError(t, err)
+	_, err = db.Exec(ctx, "DELETE FROM rooms")
+	require.NoError(t, err)
+
+	for _, desk := range []models.Room{
+		{ID: "desk-1", Type: models.ResourceDesk, PendingBlocks: true, Attributes: map[string]string{"floor": "3", "monitor": "yes"}},
+		{ID: "desk-2", Type: models.ResourceDesk, PendingBlocks: true, Attributes: map[string]string{"floor": "3"}},
+		{ID: "desk-3", Type: models.ResourceDesk, PendingBlocks: true, Attributes: map[string]string{"floor": "4", "monitor": "yes"}},
+	} {
+		require.NoError(t, service.UpdateRoom(ctx, &desk))
+	}
+
+	base := time.Now().Add(24 * time.Hour).Truncate(time.Hour)
+	require.NoError(t, service.Create(ctx, &models.Reservation{RoomID: "desk-2", StartTime: base, EndTime: base.Add(8 * time.Hour)}))
+	require.NoError(t, service.Create(ctx, &models.Reservation{RoomID: "501", StartTime: base, EndTime: base.Add(time.Hour)}))
+
+	ids := func(resources []models.Room) []string {
+		var ids []string
+		for _, resource := range resources {
+			ids = append(ids, resource.ID)
+		}
+		return ids
+	}
+
+	t.Run("listing by attributes", func(t *testing.T) {
+		resources, err := service.ListResources(ctx, models.ResourceQuery{Type: models.ResourceDesk, Attributes: map[string]string{"floor": "3"}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"desk-1", "desk-2"}, ids(resources))
+	})
+
+	t.Run("search for free resources", func(t *testing.T) {
+		resources, err := service.ListResources(ctx, models.ResourceQuery{Type: models.ResourceDesk, StartTime: base.Add(time.Hour), EndTime: base.Add(2 * time.Hour)})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"desk-1", "desk-3"}, ids(resources))
+	})
+
+	t.Run("rooms booked without settings are rooms", func(t *testing.T) {
+		resources, err := service.ListResources(ctx, models.ResourceQuery{Type: models.ResourceRoom})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"501"}, ids(resources))
+
+		_, err = service.GetResource(ctx, models.ResourceDesk, "501")
+		assert.ErrorIs(t, err, models.ErrResourceNotFound)
+	})
+
+	t.Run("conflicts suggest resources of the same type", func(t *testing.T) {
+		err := service.Create(ctx, &models.Reservation{RoomID: "desk-2", StartTime: base.Add(time.Hour), EndTime: base.Add(2 * time.Hour)})
+		var conflictErr *models.ConflictError
+		require.ErrorAs(t, err, &conflictErr)
+		assert.Equal(t, []string{"desk-1", "desk-3"}, conflictErr.SuggestedRooms)
+
+		err = service.Create(ctx, &models.Reservation{RoomID: "501", StartTime: base, EndTime: base.Add(time.Hour)})
+		require.ErrorAs(t, err, &conflictErr)
+		assert.Empty(t, conflictErr.SuggestedRooms)
+	})
+
+	t.Run("unknown type", func(t *testing.T) {
+		_, err := service.ListResources(ctx, models.ResourceQuery{Type: "spaceship"})
+		assert.ErrorIs(t, err, models.ErrInvalidParameter)
+	})
+}
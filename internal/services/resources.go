@@ -0,0 +1,41 @@
+package services
+
+import (
+	"context"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+)
+
+// GetResource implements models.ReservationService. A resource of another
+// type is not found.
+func (r *reservationService) GetResource(ctx context.Context, resourceType, id string) (*models.Room, error) {
+	room, err := r.reservationStorage.GetRoom(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if room.Type != resourceType {
+		return nil, models.ErrResourceNotFound
+	}
+	return room, nil
+}
+
+// ListResources implements models.ReservationService. The interval is
+// optional but, when given, goes through TimeValidator like a reservation.
+func (r *reservationService) ListResources(ctx context.Context, query models.ResourceQuery) ([]models.Room, error) {
+	if !models.IsResourceType(query.Type) {
+		return nil, models.ErrInvalidParameter.WithField("type")
+	}
+	if !query.StartTime.IsZero() || !query.EndTime.IsZero() {
+		if err := TimeValidator(query.StartTime, query.EndTime); err != nil {
+			return nil, err
+		}
+	}
+	if query.Limit <= 0 {
+		query.Limit = defaultPageSize
+	}
+	if query.Limit > maxPageSize {
+		query.Limit = maxPageSize
+	}
+
+	return r.reservationStorage.ListResources(ctx, query)
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+)
+
+// Sweeper periodically expires what was only reserved for a while: holds
+// and waitlist offers, and, when NoShowGrace is set, reservations nobody
+// checked in to. Their intervals go to the waitlist.
+type Sweeper struct {
+	Service     models.ReservationService
+	Interval    time.Duration
+	NoShowGrace time.Duration
+}
+
+func NewSweeper(service models.ReservationService) *Sweeper {
+	return &Sweeper{
+		Service:  service,
+		Interval: 30 * time.Second,
+	}
+}
+
+// Run sweeps until ctx is cancelled.
+func (s *Sweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := s.RunOnce(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Sweep failed: %v", err)
+		}
+	}
+}
+
+// RunOnce expires the holds and offers that ran out and releases no-shows.
+func (s *Sweeper) RunOnce(ctx context.Context) error {
+	if err := s.Service.ExpireHolds(ctx); err != nil {
+		return err
+	}
+	if err := s.Service.ExpireWaitlistOffers(ctx); err != nil {
+		return err
+	}
+	if s.NoShowGrace <= 0 {
+		return nil
+	}
+	return s.Service.ReleaseNoShows(ctx, s.NoShowGrace)
+}
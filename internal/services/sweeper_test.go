@@ -0,0 +1,41 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models/mocks"
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSweeper(t *testing.T) {
+	t.Run("expires holds and offers", func(t *testing.T) {
+		service := mocks.NewReservationService(t)
+		service.On("ExpireHolds", mock.Anything).Return(nil).Once()
+		service.On("ExpireWaitlistOffers", mock.Anything).Return(nil).Once()
+
+		assert.NoError(t, services.NewSweeper(service).RunOnce(context.Background()))
+	})
+
+	t.Run("releases no-shows after the grace period", func(t *testing.T) {
+		service := mocks.NewReservationService(t)
+		service.On("ExpireHolds", mock.Anything).Return(nil).Once()
+		service.On("ExpireWaitlistOffers", mock.Anything).Return(nil).Once()
+		service.On("ReleaseNoShows", mock.Anything, 10*time.Minute).Return(nil).Once()
+
+		sweeper := services.NewSweeper(service)
+		sweeper.NoShowGrace = 10 * time.Minute
+		assert.NoError(t, sweeper.RunOnce(context.Background()))
+	})
+
+	t.Run("stops at the first failure", func(t *testing.T) {
+		service := mocks.NewReservationService(t)
+		service.On("ExpireHolds", mock.Anything).Return(errors.New("db is down")).Once()
+
+		assert.Error(t, services.NewSweeper(service).RunOnce(context.Background()))
+	})
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+)
+
+// waitlistClaimWindow is how long a waitlisted user has to claim a freed
+// slot before it is offered to the next one.
+const waitlistClaimWindow = 15 * time.Minute
+
+// JoinWaitlist implements models.ReservationService. Only intervals that are
+// currently taken can be waited for.
+func (r *reservationService) JoinWaitlist(ctx context.Context, entry *models.WaitlistEntry) error {
+	if err := TimeValidator(entry.StartTime, entry.EndTime); err != nil {
+		return err
+	}
+	if entry.RoomID == "" {
+		return models.ErrInvalidParameter.WithField("room_id")
+	}
+	if entry.UserID == "" {
+		return models.ErrInvalidParameter.WithField("user_id")
+	}
+
+	unlock, err := r.lockRoom(ctx, entry.RoomID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	reservation := entry.Reservation()
+	fits, err := r.fits(ctx, &reservation, true, nil)
+	if err != nil {
+		return err
+	}
+	if fits {
+		return models.ErrSlotAvailable
+	}
+
+	return r.reservationStorage.CreateWaitlistEntry(ctx, entry)
+}
+
+// GetWaitlistEntry implements models.ReservationService.
+func (r *reservationService) GetWaitlistEntry(ctx context.Context, id int) (*models.WaitlistEntry, error) {
+	return r.reservationStorage.GetWaitlistEntry(ctx, id)
+}
+
+// ListWaitlist implements models.ReservationService.
+func (r *reservationService) ListWaitlist(ctx context.Context, roomID, userID string) ([]models.WaitlistEntry, error) {
+	return r.reservationStorage.ListWaitlist(ctx, roomID, userID)
+}
+
+// LeaveWaitlist implements models.ReservationService. A declined offer
+// passes to the next user at once.
+func (r *reservationService) LeaveWaitlist(ctx context.Context, id int) error {
+	entry, err := r.reservationStorage.WithdrawWaitlistEntry(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if entry.Status == models.WaitlistOffered {
+		r.offerFreedSlot(ctx, entry.RoomID, entry.StartTime, entry.EndTime)
+	}
+	return nil
+}
+
+// ClaimWaitlistOffer implements models.ReservationService. It books the
+// offered interval for the waitlisted user.
+func (r *reservationService) ClaimWaitlistOffer(ctx context.Context, id int) (*models.Reservation, error) {
+	entry, err := r.reservationStorage.GetWaitlistEntry(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if entry.Status != models.WaitlistOffered || !entry.OfferExpiresAt.After(time.Now()) {
+		return nil, models.ErrNoWaitlistOffer
+	}
+
+	unlock, err := r.lockRoom(ctx, entry.RoomID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	reservation := entry.Reservation()
+	fits, err := r.fits(ctx, &reservation, false, nil)
+	if err != nil {
+		return nil, err
+	}
+	if !fits {
+		return nil, r.conflictError(ctx, &reservation)
+	}
+
+	reservation.Status, err = r.initialStatus(ctx, reservation.RoomID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.reservationStorage.BookWaitlistEntry(ctx, entry, &reservation); err != nil {
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+// ExpireWaitlistOffers implements models.ReservationService. The slots of
+// expired offers go to the next users in line.
+func (r *reservationService) ExpireWaitlistOffers(ctx context.Context) error {
+	expired, err := r.reservationStorage.ExpireWaitlistOffers(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range expired {
+		r.offerFreedSlot(ctx, entry.RoomID, entry.StartTime, entry.EndTime)
+	}
+	return nil
+}
+
+// offerFreedSlot hands a freed interval to the waitlist, first come first:
+// each waiting entry that now fits is booked or offered, which in turn
+// keeps later overlapping entries waiting. The interval was freed whatever
+// happens here, so failures are only logged.
+func (r *reservationService) offerFreedSlot(ctx context.Context, roomID string, startTime, endTime time.Time) {
+	unlock, err := r.lockRoom(ctx, roomID)
+	if err != nil {
+		log.Printf("Failed to lock room %s: %v", roomID, err)
+		return
+	}
+	defer unlock()
+
+	candidates, err := r.reservationStorage.GetWaitlistCandidates(ctx, roomID, startTime, endTime)
+	if err != nil {
+		log.Printf("Failed to load the waitlist of room %s: %v", roomID, err)
+		return
+	}
+
+	for i := range candidates {
+		entry := &candidates[i]
+		reservation := entry.Reservation()
+		fits, err := r.fits(ctx, &reservation, true, nil)
+		if err != nil {
+			log.Printf("Failed to check waitlist entry %d: %v", entry.ID, err)
+			return
+		}
+		if !fits {
+			continue
+		}
+
+		if entry.AutoBook {
+			reservation.Status, err = r.initialStatus(ctx, roomID)
+			if err == nil {
+				err = r.reservationStorage.BookWaitlistEntry(ctx, entry, &reservation)
+			}
+		} else {
+			err = r.reservationStorage.OfferWaitlistEntry(ctx, entry, time.Now().Add(waitlistClaimWindow))
+		}
+		if err != nil {
+			log.Printf("Failed to offer a freed slot to waitlist entry %d: %v", entry.ID, err)
+		}
+	}
+}
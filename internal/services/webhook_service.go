@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/url"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+)
+
+const (
+	defaultDeliveryLogSize = 50
+	maxDeliveryLogSize     = 500
+)
+
+type webhookService struct {
+	webhookStorage models.WebhookStorage
+}
+
+// Publish implements models.EventPublisher by queueing a delivery for every
+// matching webhook. The worker sends them later.
+func (s *webhookService) Publish(ctx context.Context, event models.ReservationEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	return s.webhookStorage.EnqueueDeliveries(ctx, event, payload)
+}
+
+// CreateWebhook implements models.WebhookService. A secret is generated when
+// none is given.
+func (s *webhookService) CreateWebhook(ctx context.Context, webhook *models.Webhook) error {
+	target, err := url.Parse(webhook.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return models.ErrInvalidParameter.WithField("url")
+	}
+
+	for _, eventType := range webhook.Events {
+		if !containsString(models.EventTypes, eventType) {
+			return models.ErrInvalidParameter.WithField("events")
+		}
+	}
+	if webhook.Events == nil {
+		webhook.Events = []string{}
+	}
+	if webhook.RoomIDs == nil {
+		webhook.RoomIDs = []string{}
+	}
+
+	if webhook.Secret == "" {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return err
+		}
+		webhook.Secret = hex.EncodeToString(secret)
+	}
+
+	return s.webhookStorage.CreateWebhook(ctx, webhook)
+}
+
+// ListWebhooks implements models.WebhookService.
+func (s *webhookService) ListWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	return s.webhookStorage.ListWebhooks(ctx)
+}
+
+// DeleteWebhook implements models.WebhookService.
+func (s *webhookService) DeleteWebhook(ctx context.Context, id int) error {
+	return s.webhookStorage.DeleteWebhook(ctx, id)
+}
+
+// ListDeliveries implements models.WebhookService.
+func (s *webhookService) ListDeliveries(ctx context.Context, webhookID int, limit int) ([]models.WebhookDelivery, error) {
+	if limit <= 0 {
+		limit = defaultDeliveryLogSize
+	}
+	if limit > maxDeliveryLogSize {
+		limit = maxDeliveryLogSize
+	}
+
+	return s.webhookStorage.ListDeliveries(ctx, webhookID, limit)
+}
+
+// RetryDelivery implements models.WebhookService.
+func (s *webhookService) RetryDelivery(ctx context.Context, webhookID int, deliveryID int64) error {
+	return s.webhookStorage.RetryDelivery(ctx, webhookID, deliveryID)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func NewWebhookService(webhookStorage models.WebhookStorage) models.WebhookService {
+	return &webhookService{
+		webhookStorage: webhookStorage,
+	}
+}
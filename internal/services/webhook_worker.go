@@ -0,0 +1,159 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+)
+
+// Headers sent with every webhook delivery.
+const (
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	WebhookSignatureHeader = "X-Webhook-Signature"
+)
+
+// WebhookWorker sends queued webhook deliveries. Failed attempts are retried
+// with exponential backoff until MaxAttempts is reached, after which the
+// delivery is dead and only an explicit retry sends it again.
+type WebhookWorker struct {
+	Storage      models.WebhookStorage
+	Client       *http.Client
+	PollInterval time.Duration
+	BatchSize    int
+	MaxAttempts  int
+	BaseBackoff  time.Duration
+	MaxBackoff   time.Duration
+}
+
+func NewWebhookWorker(storage models.WebhookStorage) *WebhookWorker {
+	return &WebhookWorker{
+		Storage:      storage,
+		Client:       &http.Client{Timeout: 10 * time.Second},
+		PollInterval: time.Second,
+		BatchSize:    20,
+		MaxAttempts:  8,
+		BaseBackoff:  30 * time.Second,
+		MaxBackoff:   6 * time.Hour,
+	}
+}
+
+// Run delivers webhooks until ctx is cancelled.
+func (w *WebhookWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.RunOnce(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Webhook delivery failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce claims one batch of due deliveries and attempts each of them.
+func (w *WebhookWorker) RunOnce(ctx context.Context) error {
+	// the lease outlives an attempt, so nobody else picks the batch up
+	lease := time.Duration(w.BatchSize+1) * w.Client.Timeout
+	deliveries, err := w.Storage.ClaimDeliveries(ctx, w.BatchSize, lease)
+	if err != nil {
+		return err
+	}
+
+	for i := range deliveries {
+		w.attempt(ctx, &deliveries[i])
+		if err := w.Storage.CompleteDelivery(ctx, &deliveries[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (w *WebhookWorker) attempt(ctx context.Context, delivery *models.WebhookDelivery) {
+	now := time.Now()
+	delivery.Attempts++
+
+	err := w.send(ctx, delivery, now)
+	if err == nil {
+		delivery.Status = models.DeliveryDelivered
+		delivery.DeliveredAt = &now
+		delivery.LastError = ""
+		return
+	}
+
+	delivery.LastError = err.Error()
+	if delivery.Attempts >= w.MaxAttempts {
+		delivery.Status = models.DeliveryDead
+		return
+	}
+	delivery.Status = models.DeliveryPending
+	delivery.NextAttemptAt = now.Add(w.backoff(delivery.Attempts))
+}
+
+func (w *WebhookWorker) send(ctx context.Context, delivery *models.WebhookDelivery, now time.Time) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return err
+	}
+
+	timestamp := now.Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, delivery.EventType)
+	req.Header.Set(WebhookDeliveryHeader, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(WebhookSignatureHeader, "sha256="+SignWebhook(delivery.Secret, timestamp, delivery.Payload))
+
+	resp, err := w.Client.Do(req)
+	if err != nil {
+		delivery.ResponseStatus = 0
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	delivery.ResponseStatus = resp.StatusCode
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("receiver responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// backoff doubles the delay after every failed attempt, up to MaxBackoff.
+func (w *WebhookWorker) backoff(attempts int) time.Duration {
+	delay := w.BaseBackoff
+	for i := 1; i < attempts && delay < w.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > w.MaxBackoff {
+		delay = w.MaxBackoff
+	}
+	return delay
+}
+
+// SignWebhook returns the hex HMAC-SHA256 of "timestamp.payload" under the
+// webhook secret. Receivers recompute it to authenticate a delivery and
+// reject stale timestamps to prevent replays.
+func SignWebhook(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
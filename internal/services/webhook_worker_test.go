@@ -0,0 +1,100 @@
+package services_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models/mocks"
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookWorker(t *testing.T) {
+	ctx := context.Background()
+	payload := []byte(`{"type":"reservation.created"}`)
+
+	newWorker := func(t *testing.T, url string, attempts int) (*services.WebhookWorker, *models.WebhookDelivery) {
+		completed := &models.WebhookDelivery{}
+		storage := mocks.NewWebhookStorage(t)
+		storage.On("ClaimDeliveries", mock.Anything, mock.Anything, mock.Anything).Return([]models.WebhookDelivery{{
+			ID:        42,
+			WebhookID: 1,
+			EventType: models.EventReservationCreated,
+			Payload:   payload,
+			Status:    models.DeliveryPending,
+			Attempts:  attempts,
+			URL:       url,
+			Secret:    "s3cret",
+		}}, nil)
+		storage.On("CompleteDelivery", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			*completed = *args.Get(1).(*models.WebhookDelivery)
+		}).Return(nil)
+
+		worker := services.NewWebhookWorker(storage)
+		worker.Client.Timeout = time.Second
+		return worker, completed
+	}
+
+	t.Run("signed delivery", func(t *testing.T) {
+		var received *http.Request
+		var body []byte
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received = r
+			body, _ = io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer receiver.Close()
+
+		worker, completed := newWorker(t, receiver.URL, 0)
+		require.NoError(t, worker.RunOnce(ctx))
+
+		require.NotNil(t, received)
+		assert.Equal(t, payload, body)
+		assert.Equal(t, "42", received.Header.Get(services.WebhookDeliveryHeader))
+		assert.Equal(t, models.EventReservationCreated, received.Header.Get(services.WebhookEventHeader))
+		timestamp, err := strconv.ParseInt(received.Header.Get(services.WebhookTimestampHeader), 10, 64)
+		require.NoError(t, err)
+		assert.Equal(t, "sha256="+services.SignWebhook("s3cret", timestamp, payload), received.Header.Get(services.WebhookSignatureHeader))
+
+		assert.Equal(t, models.DeliveryDelivered, completed.Status)
+		assert.Equal(t, 1, completed.Attempts)
+		assert.NotNil(t, completed.DeliveredAt)
+	})
+
+	t.Run("failed delivery backs off", func(t *testing.T) {
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer receiver.Close()
+
+		worker, completed := newWorker(t, receiver.URL, 2)
+		require.NoError(t, worker.RunOnce(ctx))
+
+		assert.Equal(t, models.DeliveryPending, completed.Status)
+		assert.Equal(t, 3, completed.Attempts)
+		assert.Equal(t, http.StatusInternalServerError, completed.ResponseStatus)
+		assert.WithinDuration(t, time.Now().Add(4*worker.BaseBackoff), completed.NextAttemptAt, 5*time.Second)
+	})
+
+	t.Run("last attempt is dead-lettered", func(t *testing.T) {
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer receiver.Close()
+
+		worker, completed := newWorker(t, receiver.URL, 7)
+		require.NoError(t, worker.RunOnce(ctx))
+
+		assert.Equal(t, models.DeliveryDead, completed.Status)
+		assert.Equal(t, 8, completed.Attempts)
+		assert.Contains(t, completed.LastError, "502")
+	})
+}
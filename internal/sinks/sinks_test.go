@@ -0,0 +1,84 @@
+package sinks_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/sinks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var event = models.ReservationEvent{
+	ID:          7,
+	Type:        models.EventReservationCreated,
+	Reservation: models.Reservation{ID: 1, RoomID: "411"},
+}
+
+func TestWriterSink(t *testing.T) {
+	var b strings.Builder
+	sink := sinks.NewWriterSink(&b)
+
+	require.NoError(t, sink.Publish(context.Background(), event))
+	require.NoError(t, sink.Publish(context.Background(), event))
+
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+	require.Len(t, lines, 2)
+	var got models.ReservationEvent
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &got))
+	assert.Equal(t, int64(7), got.ID)
+}
+
+func TestNATSSink(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+
+		fmt.Fprint(conn, "INFO {\"server_id\":\"test\"}\r\n")
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimSpace(line)
+			switch {
+			case line == "PING":
+				fmt.Fprint(conn, "PONG\r\n")
+			case strings.HasPrefix(line, "PUB "):
+				var subject string
+				var size int
+				fmt.Sscanf(line, "PUB %s %d", &subject, &size)
+				payload := make([]byte, size+2)
+				io.ReadFull(reader, payload)
+				received <- subject + " " + string(payload[:size])
+			}
+		}
+	}()
+
+	sink, err := sinks.NewNATSSink("nats://"+listener.Addr().String(), "reservations")
+	require.NoError(t, err)
+	defer sink.Close()
+
+	require.NoError(t, sink.Publish(context.Background(), event))
+
+	msg := <-received
+	subject, payload, _ := strings.Cut(msg, " ")
+	assert.Equal(t, "reservations.created", subject)
+	assert.Contains(t, payload, `"room_id":"411"`)
+}
@@ -0,0 +1,43 @@
+// Package sinks holds the destinations the outbox relay publishes
+// reservation events to, besides webhooks.
+package sinks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+)
+
+// WriterSink writes every event as one JSON line, for stdout or an
+// append-only file.
+type WriterSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewWriterSink(w io.Writer) *WriterSink {
+	return &WriterSink{w: w}
+}
+
+// Publish implements models.EventPublisher. Files are synced before
+// returning so a published event survives a crash.
+func (s *WriterSink) Publish(_ context.Context, event models.ReservationEvent) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.w.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	if syncer, ok := s.w.(interface{ Sync() error }); ok {
+		return syncer.Sync()
+	}
+	return nil
+}
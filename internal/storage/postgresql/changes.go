@@ -0,0 +1,120 @@
+package postgresql
+
+import (
+	"context"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// changeSeqLockKey is held by readers while they hand out change sequence
+// values and outbox positions, so two readers never number rows at once.
+// Writers do not take it.
+const changeSeqLockKey = 0x6368616e6765
+
+// Writers only record the id of their transaction. A reader numbers the
+// rows whose transaction is older than the oldest one still running: those
+// are committed, and every row written from then on gets a newer
+// transaction id. A reader thus never sees N+1 while N may still appear;
+// rows of a long transaction are held back until it ends.
+const (
+	sequenceChanges = `
+		WITH pending AS (
+			SELECT id, change_xid FROM reservations
+			WHERE change_xid < pg_snapshot_xmin(pg_current_snapshot())
+			FOR UPDATE SKIP LOCKED
+		), numbered AS (
+			SELECT id, nextval('reservation_change_seq') AS seq
+			FROM (SELECT id FROM pending ORDER BY change_xid, id) p
+		)
+		UPDATE reservations r
+		SET change_seq = n.seq, change_xid = NULL
+		FROM numbered n
+		WHERE r.id = n.id
+	`
+
+	sequenceEvents = `
+		WITH pending AS (
+			SELECT id, xid FROM outbox
+			WHERE seq IS NULL AND xid < pg_snapshot_xmin(pg_current_snapshot())
+			FOR UPDATE SKIP LOCKED
+		), numbered AS (
+			SELECT id, nextval('outbox_seq') AS seq
+			FROM (SELECT id FROM pending ORDER BY xid, id) p
+		)
+		UPDATE outbox o
+		SET seq = n.seq
+		FROM numbered n
+		WHERE o.id = n.id
+	`
+)
+
+// sequence takes the change sequence lock and runs one of the numbering
+// queries. The lock is held until the transaction ends, so the numbers are
+// committed before another reader hands out the next ones.
+func sequence(ctx context.Context, tx pgx.Tx, query string) error {
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, changeSeqLockKey); err != nil {
+		return err
+	}
+
+	_, err := tx.Exec(ctx, query)
+	return err
+}
+
+// recordChange marks the reservation as changed by the current
+// transaction. It gets its change sequence once the transaction is older
+// than every running one, see GetChanges.
+func recordChange(ctx context.Context, tx pgx.Tx, id int) error {
+	_, err := tx.Exec(ctx, `UPDATE reservations SET change_xid = pg_current_xact_id() WHERE id = $1`, id)
+	return err
+}
+
+// GetChanges implements models.ReservationStorage. Every reservation
+// appears once, in its latest state. Changes are numbered when read.
+func (s *Storage) GetChanges(ctx context.Context, since int64, limit int) ([]models.Change, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := sequence(ctx, tx, sequenceChanges); err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT
+				` + reservationColumns + `, change_seq
+		FROM
+				reservations
+		WHERE
+				change_seq > $1
+		ORDER BY
+				change_seq
+		LIMIT $2
+	`
+
+	rows, err := tx.Query(ctx, query, since, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := []models.Change{}
+	for rows.Next() {
+		var change models.Change
+		change.Reservation, err = scanReservation(rows, &change.Seq)
+		if err != nil {
+			return nil, err
+		}
+		change.Deleted = change.Reservation.CancelledAt != nil
+
+		changes = append(changes, change)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return changes, tx.Commit(ctx)
+}
@@ -0,0 +1,74 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// CheckIn implements models.ReservationStorage.
+func (s *Storage) CheckIn(ctx context.Context, reservation *models.Reservation) error {
+	query := `
+		UPDATE reservations
+		SET checked_in_at = $2, updated_at = NOW(), version = version + 1
+		WHERE id = $1
+			AND cancelled_at IS NULL
+			AND checked_in_at IS NULL
+		RETURNING ` + reservationColumns
+
+	return s.inTx(ctx, func(tx pgx.Tx) error {
+		checkedIn, err := scanReservation(tx.QueryRow(ctx, query, reservation.ID, time.Now()))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.ErrNoMatchingReservation
+		}
+		if err != nil {
+			return err
+		}
+
+		*reservation = checkedIn
+		return writeEvent(ctx, tx, models.EventReservationCheckedIn, reservation)
+	})
+}
+
+// ReleaseNoShows implements models.ReservationStorage. Released
+// reservations are cancelled like any other, so their events and change
+// feed entries tell subscribers the room is free again.
+func (s *Storage) ReleaseNoShows(ctx context.Context, startedBefore time.Time) ([]models.Reservation, error) {
+	query := `
+		UPDATE reservations
+		SET cancelled_at = NOW(), status = 'cancelled', no_show = TRUE, updated_at = NOW(), version = version + 1
+		WHERE status = 'approved'
+			AND cancelled_at IS NULL
+			AND checked_in_at IS NULL
+			AND start_time <= $1
+			AND end_time > $2
+		RETURNING ` + reservationColumns
+
+	var released []models.Reservation
+	err := s.inTx(ctx, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, query, startedBefore, time.Now())
+		if err != nil {
+			return err
+		}
+
+		released, err = collectReservations(rows)
+		if err != nil {
+			return err
+		}
+
+		for i := range released {
+			if err := writeEvent(ctx, tx, models.EventReservationCancelled, &released[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return released, nil
+}
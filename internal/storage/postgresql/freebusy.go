@@ -0,0 +1,77 @@
+package postgresql
+
+import (
+	"context"
+	"time"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+)
+
+// GetBusy implements models.ReservationRepository. Busy intervals of all
+// rooms are merged in a single query: an interval starts a new island
+// unless it begins before the latest end seen so far in its room. A room is
+// busy whenever its combined room or one of its parts is.
+func (s *Storage) GetBusy(ctx context.Context, roomIDs []string, startTime time.Time, endTime time.Time) (map[string][]models.TimeSlot, error) {
+	query := `
+		WITH busy AS (
+			SELECT
+					q.room_id,
+					GREATEST(reservations.start_time, $2) AS start_time,
+					LEAST(reservations.end_time, $3) AS end_time
+			FROM
+					unnest($1::varchar[]) AS q(room_id)
+					JOIN reservations ON reservations.room_id IN ` + linkedRooms("q.room_id") + `
+			WHERE
+					reservations.cancelled_at IS NULL
+					AND ` + blocksSlot("reservations") + `
+					AND reservations.start_time < $3
+					AND reservations.end_time > $2
+		), marked AS (
+			SELECT
+					*,
+					CASE WHEN start_time <= MAX(end_time) OVER (
+						PARTITION BY room_id ORDER BY start_time, end_time
+						ROWS BETWEEN UNBOUNDED PRECEDING AND 1 PRECEDING
+					) THEN 0 ELSE 1 END AS island_start
+			FROM
+					busy
+		), islands AS (
+			SELECT
+					*,
+					SUM(island_start) OVER (PARTITION BY room_id ORDER BY start_time, end_time) AS island
+			FROM
+					marked
+		)
+		SELECT
+				room_id, MIN(start_time), MAX(end_time)
+		FROM
+				islands
+		GROUP BY
+				room_id, island
+		ORDER BY
+				room_id, MIN(start_time)
+	`
+
+	rows, err := s.db.Query(ctx, query, roomIDs, startTime, endTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	busy := make(map[string][]models.TimeSlot)
+	for rows.Next() {
+		var roomID string
+		var slot models.TimeSlot
+		if err := rows.Scan(&roomID, &slot.StartTime, &slot.EndTime); err != nil {
+			return nil, err
+		}
+
+		busy[roomID] = append(busy[roomID], slot)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return busy, nil
+}
@@ -0,0 +1,191 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+	"github.com/jackc/pgx/v5"
+)
+
+const holdColumns = `id, room_id, user_id, start_time, end_time, status, expires_at, COALESCE(reservation_id, 0), created_at`
+
+func scanHold(row pgx.Row) (models.Hold, error) {
+	var hold models.Hold
+	err := row.Scan(
+		&hold.ID,
+		&hold.RoomID,
+		&hold.UserID,
+		&hold.StartTime,
+		&hold.EndTime,
+		&hold.Status,
+		&hold.ExpiresAt,
+		&hold.ReservationID,
+		&hold.CreatedAt,
+	)
+	return hold, err
+}
+
+// IsHeld implements models.ReservationStorage. Holds past their expiry no
+// longer count even before the sweeper marks them expired.
+func (s *Storage) IsHeld(ctx context.Context, roomID string, startTime time.Time, endTime time.Time) (bool, error) {
+	query := `
+		SELECT
+				EXISTS (
+					SELECT 1
+					FROM holds
+					WHERE
+							room_id IN ` + linkedRooms("$1") + `
+							AND status = 'active'
+							AND expires_at > $4
+							AND start_time < $3 AND end_time > $2
+				)
+				OR EXISTS (
+					SELECT 1
+					FROM waitlist
+					WHERE
+							room_id IN ` + linkedRooms("$1") + `
+							AND status = 'offered'
+							AND offer_expires_at > $4
+							AND start_time < $3 AND end_time > $2
+				)
+	`
+
+	var held bool
+	err := s.db.QueryRow(ctx, query, roomID, startTime, endTime, time.Now()).Scan(&held)
+	return held, err
+}
+
+// GetHeld implements models.ReservationStorage.
+func (s *Storage) GetHeld(ctx context.Context, roomID string, startTime time.Time, endTime time.Time) ([]models.Reservation, error) {
+	query := `
+		SELECT room_id, start_time, end_time
+		FROM holds
+		WHERE
+				room_id IN ` + linkedRooms("$1") + `
+				AND status = 'active'
+				AND expires_at > $4
+				AND start_time < $3 AND end_time > $2
+		UNION ALL
+		SELECT room_id, start_time, end_time
+		FROM waitlist
+		WHERE
+				room_id IN ` + linkedRooms("$1") + `
+				AND status = 'offered'
+				AND offer_expires_at > $4
+				AND start_time < $3 AND end_time > $2
+	`
+
+	rows, err := s.db.Query(ctx, query, roomID, startTime, endTime, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.Reservation, error) {
+		held := models.Reservation{Seats: 1}
+		err := row.Scan(&held.RoomID, &held.StartTime, &held.EndTime)
+		return held, err
+	})
+}
+
+// CreateHold implements models.ReservationStorage.
+func (s *Storage) CreateHold(ctx context.Context, hold *models.Hold) error {
+	query := `
+		INSERT INTO holds(room_id, user_id, start_time, end_time, expires_at) VALUES($1, $2, $3, $4, $5)
+		RETURNING id, status, created_at
+	`
+
+	return s.db.QueryRow(ctx, query, hold.RoomID, hold.UserID, hold.StartTime, hold.EndTime, hold.ExpiresAt).
+		Scan(&hold.ID, &hold.Status, &hold.CreatedAt)
+}
+
+// GetHold implements models.ReservationStorage.
+func (s *Storage) GetHold(ctx context.Context, id int) (*models.Hold, error) {
+	hold, err := scanHold(s.db.QueryRow(ctx, `SELECT `+holdColumns+` FROM holds WHERE id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, models.ErrHoldNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &hold, nil
+}
+
+// ReleaseHold implements models.ReservationStorage.
+func (s *Storage) ReleaseHold(ctx context.Context, id int) (*models.Hold, error) {
+	query := `
+		UPDATE holds
+		SET status = 'released'
+		WHERE id = $1 AND status = 'active'
+		RETURNING ` + holdColumns
+
+	hold, err := scanHold(s.db.QueryRow(ctx, query, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, models.ErrHoldNotActive
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &hold, nil
+}
+
+// ConfirmHold implements models.ReservationStorage.
+func (s *Storage) ConfirmHold(ctx context.Context, hold *models.Hold, reservation *models.Reservation) error {
+	query := `
+		UPDATE holds
+		SET status = 'confirmed', reservation_id = $2
+		WHERE id = $1 AND status = 'active' AND expires_at > $3
+	`
+
+	return s.inTx(ctx, func(tx pgx.Tx) error {
+		if err := insertReservation(ctx, tx, reservation); err != nil {
+			return err
+		}
+
+		res, err := tx.Exec(ctx, query, hold.ID, reservation.ID, time.Now())
+		if err != nil {
+			return err
+		}
+		if res.RowsAffected() == 0 {
+			return models.ErrHoldNotActive
+		}
+
+		hold.Status = models.HoldConfirmed
+		hold.ReservationID = reservation.ID
+		return nil
+	})
+}
+
+// ExpireHolds implements models.ReservationStorage.
+func (s *Storage) ExpireHolds(ctx context.Context) ([]models.Hold, error) {
+	query := `
+		UPDATE holds
+		SET status = 'expired'
+		WHERE status = 'active' AND expires_at <= $1
+		RETURNING ` + holdColumns
+
+	rows, err := s.db.Query(ctx, query, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	holds := []models.Hold{}
+	for rows.Next() {
+		hold, err := scanHold(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		holds = append(holds, hold)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return holds, nil
+}
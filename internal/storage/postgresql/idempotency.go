@@ -0,0 +1,111 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// staleClaimTimeout is how long a key may stay claimed without a stored
+// response before a retry is allowed to take it over, e.g. after a crash
+// between Claim and Complete.
+const staleClaimTimeout = 5 * time.Minute
+
+type IdempotencyStorage struct {
+	db *pgxpool.Pool
+}
+
+// Claim implements models.IdempotencyStorage. It prunes expired keys and
+// reports whether the record was inserted, i.e. whether the caller owns it.
+// A claim left without a response for staleClaimTimeout is taken over by a
+// request with the same hash.
+func (s *IdempotencyStorage) Claim(ctx context.Context, record *models.IdempotencyRecord) (bool, error) {
+	_, err := s.db.Exec(ctx, `DELETE FROM idempotency_keys WHERE expires_at < $1`, time.Now())
+	if err != nil {
+		return false, err
+	}
+
+	query := `
+		INSERT INTO idempotency_keys(key, method, path, request_hash, expires_at)
+		VALUES($1, $2, $3, $4, $5)
+		ON CONFLICT (key, method, path) DO UPDATE
+		SET created_at = NOW(), expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.status_code IS NULL
+			AND idempotency_keys.request_hash = EXCLUDED.request_hash
+			AND idempotency_keys.created_at < NOW() - make_interval(secs => $6)
+	`
+
+	res, err := s.db.Exec(ctx, query, record.Key, record.Method, record.Path, record.RequestHash, record.ExpiresAt, staleClaimTimeout.Seconds())
+	if err != nil {
+		return false, err
+	}
+
+	return res.RowsAffected() == 1, nil
+}
+
+// Get implements models.IdempotencyStorage.
+func (s *IdempotencyStorage) Get(ctx context.Context, key, method, path string) (*models.IdempotencyRecord, error) {
+	query := `
+		SELECT
+				request_hash, COALESCE(status_code, 0), headers, body, expires_at
+		FROM
+				idempotency_keys
+		WHERE
+				key = $1
+				AND method = $2
+				AND path = $3
+	`
+
+	record := &models.IdempotencyRecord{
+		Key:    key,
+		Method: method,
+		Path:   path,
+	}
+	err := s.db.QueryRow(ctx, query, key, method, path).
+		Scan(&record.RequestHash, &record.StatusCode, &record.Headers, &record.Body, &record.ExpiresAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// Complete implements models.IdempotencyStorage.
+func (s *IdempotencyStorage) Complete(ctx context.Context, record *models.IdempotencyRecord) error {
+	query := `
+		UPDATE idempotency_keys
+		SET status_code = $4, headers = $5, body = $6
+		WHERE key = $1
+			AND method = $2
+			AND path = $3
+	`
+
+	_, err := s.db.Exec(ctx, query, record.Key, record.Method, record.Path, record.StatusCode, record.Headers, record.Body)
+	return err
+}
+
+// Release implements models.IdempotencyStorage.
+func (s *IdempotencyStorage) Release(ctx context.Context, record *models.IdempotencyRecord) error {
+	query := `
+		DELETE FROM idempotency_keys
+		WHERE key = $1
+			AND method = $2
+			AND path = $3
+	`
+
+	_, err := s.db.Exec(ctx, query, record.Key, record.Method, record.Path)
+	return err
+}
+
+func NewIdempotencyStorage(db *pgxpool.Pool) models.IdempotencyStorage {
+	return &IdempotencyStorage{
+		db: db,
+	}
+}
@@ -0,0 +1,166 @@
+package postgresql
+
+import (
+	"context"
+	"time"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// roomListingWhere selects a page of a room listing, see listingArgs for the
+// parameters.
+const roomListingWhere = `
+		room_id = $1
+		AND cancelled_at IS NULL
+		AND end_time > $2
+		AND ($3::timestamp IS NULL OR start_time < $3)
+		AND ($4::timestamp IS NULL OR (start_time, id) > ($4, $5))
+`
+
+func listingArgs(roomID string, filter models.ReservationFilter) []any {
+	var to, afterStart *time.Time
+	var afterID int
+	if !filter.To.IsZero() {
+		to = &filter.To
+	}
+	if filter.After != nil {
+		afterStart = &filter.After.StartTime
+		afterID = filter.After.ID
+	}
+	return []any{roomID, filter.From, to, afterStart, afterID}
+}
+
+// GetByRoomID implements models.ReservationRepository. It returns at most
+// filter.Limit reservations and a cursor for the next page when more remain.
+func (s *Storage) GetByRoomID(ctx context.Context, roomID string, filter models.ReservationFilter) (*models.RoomReservations, error) {
+	query := `
+		SELECT
+		 		` + reservationColumns + `
+		FROM 
+				reservations
+		WHERE 
+				` + roomListingWhere + `
+		ORDER BY
+				start_time, id
+		LIMIT $6
+	`
+
+	rows, err := s.db.Query(ctx, query, append(listingArgs(roomID, filter), filter.Limit+1)...)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := collectReservations(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	reservations := &models.RoomReservations{
+		RoomID:       roomID,
+		Reservations: list,
+	}
+
+	if len(reservations.Reservations) > filter.Limit {
+		reservations.Reservations = reservations.Reservations[:filter.Limit]
+		last := reservations.Reservations[filter.Limit-1]
+		reservations.NextCursor = models.Cursor{StartTime: last.StartTime, ID: last.ID}.String()
+	}
+
+	return reservations, nil
+}
+
+// listingInfo summarizes the page GetByRoomID would return without reading
+// the reservations themselves.
+func listingInfo(ctx context.Context, tx pgx.Tx, roomID string, filter models.ReservationFilter) (*models.ListingInfo, error) {
+	query := `
+		WITH page AS (
+			SELECT
+					id, start_time, version,
+					ROW_NUMBER() OVER (ORDER BY start_time, id) AS n
+			FROM
+					reservations
+			WHERE
+					` + roomListingWhere + `
+			ORDER BY
+					start_time, id
+			LIMIT $6 + 1
+		)
+		SELECT
+				COALESCE(md5(string_agg(id || ':' || version, ';' ORDER BY n) FILTER (WHERE n <= $6)), ''),
+				COUNT(*) > $6,
+				MAX(start_time) FILTER (WHERE n = $6),
+				COALESCE(MAX(id) FILTER (WHERE n = $6), 0)
+		FROM
+				page
+	`
+
+	var info models.ListingInfo
+	var hasMore bool
+	var lastStart *time.Time
+	var lastID int
+	err := tx.QueryRow(ctx, query, append(listingArgs(roomID, filter), filter.Limit)...).
+		Scan(&info.Fingerprint, &hasMore, &lastStart, &lastID)
+	if err != nil {
+		return nil, err
+	}
+
+	if hasMore && lastStart != nil {
+		info.NextCursor = models.Cursor{StartTime: *lastStart, ID: lastID}.String()
+	}
+
+	return &info, nil
+}
+
+// StreamByRoomID implements models.ReservationRepository. The page summary
+// and the rows are read in one repeatable read transaction, so a write that
+// commits in between cannot make the ETag or the next cursor disagree with
+// the streamed body. fn is called for each reservation as rows arrive.
+func (s *Storage) StreamByRoomID(ctx context.Context, roomID string, filter models.ReservationFilter, begin func(models.ListingInfo) error, fn func(models.Reservation) error) error {
+	query := `
+		SELECT
+				` + reservationColumns + `
+		FROM
+				reservations
+		WHERE
+				` + roomListingWhere + `
+		ORDER BY
+				start_time, id
+		LIMIT $6
+	`
+
+	tx, err := s.db.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	info, err := listingInfo(ctx, tx, roomID, filter)
+	if err != nil {
+		return err
+	}
+	if err := begin(*info); err != nil {
+		return err
+	}
+
+	rows, err := tx.Query(ctx, query, append(listingArgs(roomID, filter), filter.Limit)...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		reservation, err := scanReservation(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(reservation); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,155 @@
+package postgresql
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const notificationColumns = `id, COALESCE(event_id, 0), kind, recipient, payload, waitlist, status, attempts, send_at, last_error, created_at, sent_at`
+
+type NotificationStorage struct {
+	db *pgxpool.Pool
+}
+
+// Enqueue implements models.NotificationStorage. An event published again
+// by the relay does not queue a second notification.
+func (s *NotificationStorage) Enqueue(ctx context.Context, notification *models.Notification) error {
+	payload, err := json.Marshal(notification.Reservation)
+	if err != nil {
+		return err
+	}
+	var waitlist []byte
+	if notification.Waitlist != nil {
+		if waitlist, err = json.Marshal(notification.Waitlist); err != nil {
+			return err
+		}
+	}
+
+	query := `
+		INSERT INTO notifications(event_id, kind, recipient, reservation_id, payload, waitlist, send_at)
+		VALUES($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (event_id, kind) DO NOTHING
+	`
+
+	_, err = s.db.Exec(ctx, query, notification.EventID, notification.Kind, notification.Recipient,
+		notification.Reservation.ID, payload, waitlist, notification.SendAt)
+	return err
+}
+
+// ScheduleReminder implements models.NotificationStorage.
+func (s *NotificationStorage) ScheduleReminder(ctx context.Context, notification *models.Notification) error {
+	payload, err := json.Marshal(notification.Reservation)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		WITH replaced AS (
+			DELETE FROM notifications
+			WHERE reservation_id = $3 AND kind = $1 AND status = 'pending'
+		)
+		INSERT INTO notifications(kind, recipient, reservation_id, payload, send_at)
+		VALUES($1, $2, $3, $4, $5)
+	`
+
+	_, err = s.db.Exec(ctx, query, models.NotificationReminder, notification.Recipient,
+		notification.Reservation.ID, payload, notification.SendAt)
+	return err
+}
+
+// CancelReminder implements models.NotificationStorage.
+func (s *NotificationStorage) CancelReminder(ctx context.Context, reservationID int) error {
+	query := `
+		DELETE FROM notifications
+		WHERE reservation_id = $1 AND kind = $2 AND status = 'pending'
+	`
+
+	_, err := s.db.Exec(ctx, query, reservationID, models.NotificationReminder)
+	return err
+}
+
+// ClaimNotifications implements models.NotificationStorage. Like webhook
+// deliveries, claimed notifications are leased rather than locked.
+func (s *NotificationStorage) ClaimNotifications(ctx context.Context, limit int, lease time.Duration) ([]models.Notification, error) {
+	query := `
+		UPDATE notifications
+		SET send_at = $2
+		WHERE id IN (
+			SELECT id
+			FROM notifications
+			WHERE status = 'pending' AND send_at <= $3
+			ORDER BY send_at, id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + notificationColumns
+
+	now := time.Now()
+	rows, err := s.db.Query(ctx, query, limit, now.Add(lease), now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	notifications := []models.Notification{}
+	for rows.Next() {
+		var notification models.Notification
+		var payload, waitlist []byte
+		err := rows.Scan(
+			&notification.ID,
+			&notification.EventID,
+			&notification.Kind,
+			&notification.Recipient,
+			&payload,
+			&waitlist,
+			&notification.Status,
+			&notification.Attempts,
+			&notification.SendAt,
+			&notification.LastError,
+			&notification.CreatedAt,
+			&notification.SentAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(payload, &notification.Reservation); err != nil {
+			return nil, err
+		}
+		if waitlist != nil {
+			if err := json.Unmarshal(waitlist, &notification.Waitlist); err != nil {
+				return nil, err
+			}
+		}
+
+		notifications = append(notifications, notification)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return notifications, nil
+}
+
+// CompleteNotification implements models.NotificationStorage.
+func (s *NotificationStorage) CompleteNotification(ctx context.Context, notification *models.Notification) error {
+	query := `
+		UPDATE notifications
+		SET status = $2, attempts = $3, send_at = $4, last_error = $5, sent_at = $6
+		WHERE id = $1
+	`
+
+	_, err := s.db.Exec(ctx, query, notification.ID, notification.Status, notification.Attempts,
+		notification.SendAt, notification.LastError, notification.SentAt)
+	return err
+}
+
+func NewNotificationStorage(db *pgxpool.Pool) models.NotificationStorage {
+	return &NotificationStorage{
+		db: db,
+	}
+}
@@ -0,0 +1,257 @@
+package postgresql
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	// outboxLockKey is the advisory lock held while a relay claims events,
+	// so two instances never claim events of the same room at once.
+	outboxLockKey = 0x6f7574626f78
+
+	// outboxRetention is how long published events are kept for inspection
+	// and for event streams resuming after a disconnect.
+	outboxRetention = 7 * 24 * time.Hour
+
+	// outboxChannel is notified with the ID of every event when the
+	// transaction that wrote it commits.
+	outboxChannel = "reservation_events"
+)
+
+// inTx runs fn in a transaction that is committed when fn succeeds.
+func (s *Storage) inTx(ctx context.Context, fn func(tx pgx.Tx) error) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// writeEvent appends a reservation event to the outbox in the transaction
+// that made the change, so the event exists if and only if the change was
+// committed. It also marks the reservation as changed, see recordChange.
+func writeEvent(ctx context.Context, tx pgx.Tx, eventType string, reservation *models.Reservation) error {
+	if err := recordChange(ctx, tx, reservation.ID); err != nil {
+		return err
+	}
+
+	return appendEvent(ctx, tx, models.ReservationEvent{
+		Type:        eventType,
+		OccurredAt:  time.Now(),
+		Reservation: *reservation,
+	})
+}
+
+// appendEvent writes an event to the outbox and notifies listeners of it
+// on commit. The event gets its stream position when it is read, like a
+// change sequence, see EventsAfter.
+func appendEvent(ctx context.Context, tx pgx.Tx, event models.ReservationEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		WITH event AS (
+			INSERT INTO outbox(room_id, event_type, payload, xid) VALUES($1, $2, $3, pg_current_xact_id())
+			RETURNING id
+		)
+		SELECT pg_notify('` + outboxChannel + `', id::text) FROM event
+	`
+
+	_, err = tx.Exec(ctx, query, event.Reservation.RoomID, event.Type, payload)
+	return err
+}
+
+type OutboxStorage struct {
+	db *pgxpool.Pool
+}
+
+// ClaimEvents implements models.OutboxStorage. Rooms with a claimed
+// unpublished event are skipped, so a room whose sink keeps failing waits
+// for its lease to run out without holding back other rooms. Rooms take
+// turns: the first event of every room is claimed before the second of any.
+func (s *OutboxStorage) ClaimEvents(ctx context.Context, limit int, lease time.Duration) ([]models.ReservationEvent, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var locked bool
+	if err := tx.QueryRow(ctx, `SELECT pg_try_advisory_xact_lock($1)`, outboxLockKey).Scan(&locked); err != nil {
+		return nil, err
+	}
+	if !locked {
+		return nil, nil
+	}
+
+	now := time.Now()
+	if _, err := tx.Exec(ctx, `DELETE FROM outbox WHERE published_at < $1`, now.Add(-outboxRetention)); err != nil {
+		return nil, err
+	}
+
+	query := `
+		WITH candidates AS (
+			SELECT
+					id, ROW_NUMBER() OVER (PARTITION BY room_id ORDER BY id) AS n
+			FROM
+					outbox
+			WHERE
+					published_at IS NULL
+					AND room_id NOT IN (
+						SELECT room_id FROM outbox
+						WHERE published_at IS NULL AND claimed_until > $2
+					)
+		)
+		UPDATE outbox o
+		SET claimed_until = $3
+		FROM (SELECT id FROM candidates ORDER BY n, id LIMIT $1) c
+		WHERE o.id = c.id
+		RETURNING o.id, COALESCE(o.seq, 0), o.payload
+	`
+
+	rows, err := tx.Query(ctx, query, limit, now, now.Add(lease))
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := collectEvents(rows)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].ID < events[j].ID
+	})
+
+	return events, tx.Commit(ctx)
+}
+
+// MarkPublished implements models.OutboxStorage.
+func (s *OutboxStorage) MarkPublished(ctx context.Context, ids []int64) error {
+	_, err := s.db.Exec(ctx, `UPDATE outbox SET published_at = NOW(), claimed_until = NULL WHERE id = ANY($1)`, ids)
+	return err
+}
+
+// LastSeq implements models.OutboxStorage.
+func (s *OutboxStorage) LastSeq(ctx context.Context) (int64, error) {
+	var seq int64
+	err := s.db.QueryRow(ctx, `SELECT COALESCE(MAX(seq), 0) FROM outbox`).Scan(&seq)
+	return seq, err
+}
+
+// EventsAfter implements models.OutboxStorage. It first gives a stream
+// position to the events whose transaction is older than every running
+// one, see sequenceEvents.
+func (s *OutboxStorage) EventsAfter(ctx context.Context, afterSeq int64, roomID string, limit int) ([]models.ReservationEvent, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := sequence(ctx, tx, sequenceEvents); err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT
+				id, seq, payload
+		FROM
+				outbox
+		WHERE
+				seq > $1
+				AND ($2 = '' OR room_id = $2)
+		ORDER BY
+				seq
+		LIMIT $3
+	`
+
+	rows, err := tx.Query(ctx, query, afterSeq, roomID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := collectEvents(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return events, tx.Commit(ctx)
+}
+
+// Listen implements models.OutboxStorage on a connection taken out of the
+// pool for as long as it listens.
+func (s *OutboxStorage) Listen(ctx context.Context, fn func(id int64)) error {
+	conn, err := s.db.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	pgConn := conn.Hijack()
+	defer pgConn.Close(context.Background())
+
+	if _, err := pgConn.Exec(ctx, "LISTEN "+outboxChannel); err != nil {
+		return err
+	}
+
+	for {
+		notification, err := pgConn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		id, err := strconv.ParseInt(notification.Payload, 10, 64)
+		if err != nil {
+			continue
+		}
+		fn(id)
+	}
+}
+
+func collectEvents(rows pgx.Rows) ([]models.ReservationEvent, error) {
+	defer rows.Close()
+
+	events := []models.ReservationEvent{}
+	for rows.Next() {
+		var id, seq int64
+		var payload []byte
+		if err := rows.Scan(&id, &seq, &payload); err != nil {
+			return nil, err
+		}
+
+		var event models.ReservationEvent
+		if err := json.Unmarshal(payload, &event); err != nil {
+			return nil, err
+		}
+		event.ID = id
+		event.Seq = seq
+
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func NewOutboxStorage(db *pgxpool.Pool) models.OutboxStorage {
+	return &OutboxStorage{
+		db: db,
+	}
+}
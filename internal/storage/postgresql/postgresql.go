@@ -9,6 +9,7 @@ import (
 
 	"github.com/Seven11Eleven/meeting_room_booking_system/internal/config"
 	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -16,89 +17,203 @@ type Storage struct {
 	db *pgxpool.Pool
 }
 
-// DeleteReservation implements models.ReservationRepository.
+// DeleteReservation implements models.ReservationRepository. The interval
+// must identify a single live reservation, which is then cancelled like in
+// DeleteByID; seat bookings and pending requests may share an interval, so
+// several matches are rejected rather than cancelled together. A zero
+// reservation.Version cancels unconditionally.
 func (s *Storage) DeleteReservation(ctx context.Context, reservation *models.Reservation) error {
 	query := `
-		WITH matched AS (
-			SELECT 1
-			FROM reservations
-			WHERE room_id = $1
-				AND start_time = $2
-				AND end_time = $3
-		)
-		DELETE FROM reservations
+		SELECT id
+		FROM reservations
 		WHERE room_id = $1
 			AND start_time = $2
 			AND end_time = $3
-			AND EXISTS (SELECT 1 FROM matched)
-		RETURNING *;
+			AND cancelled_at IS NULL
+		LIMIT 2
 	`
 
-	res, err := s.db.Exec(ctx, query, reservation.RoomID, reservation.StartTime, reservation.EndTime)
+	rows, err := s.db.Query(ctx, query, reservation.RoomID, reservation.StartTime, reservation.EndTime)
+	if err != nil {
+		return err
+	}
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[int])
 	if err != nil {
 		return err
 	}
 
-	rowsAffected := res.RowsAffected()
-	if rowsAffected == 0 {
+	switch len(ids) {
+	case 0:
 		return models.ErrNoMatchingReservation
+	case 1:
+		reservation.ID = ids[0]
+		return s.DeleteByID(ctx, reservation)
+	default:
+		return models.ErrAmbiguousReservation
 	}
-
-	return nil
 }
 
 // Create implements models.ReservationRepository.
 func (s *Storage) Create(ctx context.Context, reservation *models.Reservation) error {
+	return s.inTx(ctx, func(tx pgx.Tx) error {
+		return insertReservation(ctx, tx, reservation)
+	})
+}
+
+// CreateBatch implements models.ReservationStorage.
+func (s *Storage) CreateBatch(ctx context.Context, reservations []models.Reservation) error {
+	return s.inTx(ctx, func(tx pgx.Tx) error {
+		for i := range reservations {
+			if err := insertReservation(ctx, tx, &reservations[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// insertReservation stores a new reservation, approved unless its status
+// says otherwise and taking one seat unless it asks for more.
+func insertReservation(ctx context.Context, tx pgx.Tx, reservation *models.Reservation) error {
 	query := `
-		INSERT INTO reservations(room_id, start_time, end_time) VALUES($1, $2, $3)
+		INSERT INTO reservations(room_id, user_id, ical_uid, start_time, end_time, status, seats, checked_in_at) VALUES($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, version, updated_at
 	`
 
-	_, err := s.db.Exec(ctx, query, reservation.RoomID, reservation.StartTime, reservation.EndTime)
+	if reservation.Status == "" {
+		reservation.Status = models.StatusApproved
+	}
+	if reservation.Seats == 0 {
+		reservation.Seats = 1
+	}
+
+	err := tx.QueryRow(ctx, query, reservation.RoomID, reservation.UserID, reservation.ICalUID, reservation.StartTime, reservation.EndTime, reservation.Status, reservation.Seats, reservation.CheckedInAt).
+		Scan(&reservation.ID, &reservation.Version, &reservation.UpdatedAt)
 	if err != nil {
 		return err
 	}
-	return nil
+	return writeEvent(ctx, tx, models.EventReservationCreated, reservation)
+}
+
+// GetByID implements models.ReservationRepository.
+func (s *Storage) GetByID(ctx context.Context, id int) (*models.Reservation, error) {
+	query := `
+		SELECT
+				` + reservationColumns + `
+		FROM
+				reservations
+		WHERE
+				id = $1
+				AND cancelled_at IS NULL
+	`
+
+	reservation, err := scanReservation(s.db.QueryRow(ctx, query, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, models.ErrNoMatchingReservation
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &reservation, nil
 }
 
-// GetByRoomID implements models.ReservationRepository.
-func (s *Storage) GetByRoomID(ctx context.Context, roomID string) (*models.RoomReservations, error) {
+// GetByICalUID implements models.ReservationRepository.
+func (s *Storage) GetByICalUID(ctx context.Context, roomID, uid string) (*models.Reservation, error) {
 	query := `
 		SELECT
-		 		start_time, end_time
-		FROM 
+				` + reservationColumns + `
+		FROM
 				reservations
-		WHERE 
+		WHERE
 				room_id = $1
+				AND ical_uid = $2
+				AND cancelled_at IS NULL
 	`
 
-	rows, err := s.db.Query(ctx, query, roomID)
+	reservation, err := scanReservation(s.db.QueryRow(ctx, query, roomID, uid))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, models.ErrNoMatchingReservation
+	}
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
 
-	reservations := &models.RoomReservations{
-		RoomID:       roomID,
-		Reservations: []models.TimeSlot{},
+	return &reservation, nil
+}
+
+// Update implements models.ReservationRepository. A zero reservation.Version
+// updates unconditionally, otherwise it must match the stored version. Zero
+// reservation.Seats keeps the booked seats.
+func (s *Storage) Update(ctx context.Context, reservation *models.Reservation) error {
+	query := `
+		UPDATE reservations
+		SET start_time = $3, end_time = $4, seats = COALESCE(NULLIF($6, 0), seats), version = version + 1, updated_at = NOW()
+		WHERE id = $1
+			AND room_id = $2
+			AND ($5 = 0 OR version = $5)
+			AND cancelled_at IS NULL
+		RETURNING user_id, ical_uid, status, seats, version, updated_at
+	`
+
+	err := s.inTx(ctx, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, query, reservation.ID, reservation.RoomID, reservation.StartTime, reservation.EndTime, reservation.Version, reservation.Seats).
+			Scan(&reservation.UserID, &reservation.ICalUID, &reservation.Status, &reservation.Seats, &reservation.Version, &reservation.UpdatedAt)
+		if err != nil {
+			return err
+		}
+		return writeEvent(ctx, tx, models.EventReservationUpdated, reservation)
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return s.versionError(ctx, reservation)
 	}
-	for rows.Next() {
-		var reservation models.TimeSlot
-		err := rows.Scan(&reservation.StartTime, &reservation.EndTime)
+	return err
+}
+
+// DeleteByID implements models.ReservationRepository. A zero
+// reservation.Version cancels unconditionally.
+func (s *Storage) DeleteByID(ctx context.Context, reservation *models.Reservation) error {
+	query := `
+		UPDATE reservations
+		SET cancelled_at = NOW(), status = 'cancelled', updated_at = NOW(), version = version + 1
+		WHERE id = $1
+			AND room_id = $2
+			AND ($3 = 0 OR version = $3)
+			AND cancelled_at IS NULL
+		RETURNING ` + reservationColumns
+
+	err := s.inTx(ctx, func(tx pgx.Tx) error {
+		cancelled, err := scanReservation(tx.QueryRow(ctx, query, reservation.ID, reservation.RoomID, reservation.Version))
 		if err != nil {
-			return nil, err
+			return err
 		}
 
-		reservations.Reservations = append(reservations.Reservations, reservation)
+		*reservation = cancelled
+		return writeEvent(ctx, tx, models.EventReservationCancelled, reservation)
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return s.versionError(ctx, reservation)
 	}
+	return err
+}
 
-	if err = rows.Err(); err != nil {
-		return nil, err
+// versionError tells a missing reservation apart from a stale version after
+// a conditional write matched no rows.
+func (s *Storage) versionError(ctx context.Context, reservation *models.Reservation) error {
+	stored, err := s.GetByID(ctx, reservation.ID)
+	if err != nil {
+		return err
 	}
-
-	return reservations, nil
+	if stored.RoomID != reservation.RoomID {
+		return models.ErrNoMatchingReservation
+	}
+	return models.ErrVersionMismatch
 }
 
-// IsReserved implements models.ReservationRepository.
+// IsReserved implements models.ReservationRepository. Reservations of
+// combined rooms and their parts block each other. Active holds and open
+// waitlist offers block their interval like reservations do, pending
+// reservations only where the room says so.
 func (s *Storage) IsReserved(ctx context.Context, roomID string, startTime time.Time, endTime time.Time) (bool, error) {
 	query := `
 	SELECT 
@@ -106,7 +221,9 @@ func (s *Storage) IsReserved(ctx context.Context, roomID string, startTime time.
 	FROM 
 		reservations
 	WHERE
-		room_id = $1
+		room_id IN ` + linkedRooms("$1") + `
+		AND cancelled_at IS NULL
+		AND ` + blocksSlot("reservations") + `
 		AND 
 		(
 			(start_time < $3 AND end_time > $2)
@@ -118,8 +235,131 @@ func (s *Storage) IsReserved(ctx context.Context, roomID string, startTime time.
 	if err != nil {
 		return false, err
 	}
+	if cnt > 0 {
+		return true, nil
+	}
+
+	return s.IsHeld(ctx, roomID, startTime, endTime)
+}
+
+// GetOverlapping implements models.ReservationRepository. It includes the
+// reservations of the combined room and of the parts.
+func (s *Storage) GetOverlapping(ctx context.Context, roomID string, startTime time.Time, endTime time.Time) ([]models.Reservation, error) {
+	query := `
+		SELECT
+				` + reservationColumns + `
+		FROM
+				reservations
+		WHERE
+				room_id IN ` + linkedRooms("$1") + `
+				AND cancelled_at IS NULL
+				AND ` + blocksSlot("reservations") + `
+				AND start_time < $3
+				AND end_time > $2
+		ORDER BY
+				start_time
+	`
+
+	rows, err := s.db.Query(ctx, query, roomID, startTime, endTime)
+	if err != nil {
+		return nil, err
+	}
+
+	return collectReservations(rows)
+}
+
+// GetCalendar implements models.ReservationRepository. Cancelled
+// reservations are included so subscribers can remove them.
+func (s *Storage) GetCalendar(ctx context.Context, feed models.CalendarFeed, since time.Time) ([]models.Reservation, error) {
+	column := "room_id"
+	if feed.Kind == models.UserFeed {
+		column = "user_id"
+	}
+
+	query := `
+		SELECT
+				` + reservationColumns + `
+		FROM
+				reservations
+		WHERE
+				` + column + ` = $1
+				AND end_time > $2
+		ORDER BY
+				start_time, id
+	`
+
+	rows, err := s.db.Query(ctx, query, feed.Subject, since)
+	if err != nil {
+		return nil, err
+	}
+
+	return collectReservations(rows)
+}
+
+// GetFreeRooms implements models.ReservationRepository. Only resources of
+// the same type as excludeRoomID are suggested; unknown ids are rooms. Like
+// in IsReserved, active holds and open waitlist offers take their slot.
+func (s *Storage) GetFreeRooms(ctx context.Context, excludeRoomID string, startTime time.Time, endTime time.Time, limit int) ([]string, error) {
+	query := `
+		WITH ` + resourcesCTE + `
+		SELECT
+				r.id
+		FROM
+				resources r
+		WHERE
+				r.id <> $1
+				AND r.type = COALESCE((SELECT type FROM rooms WHERE id = $1), '` + models.ResourceRoom + `')
+				AND NOT EXISTS (
+					SELECT 1
+					FROM reservations o
+					WHERE o.room_id IN ` + linkedRooms("r.id") + `
+						AND o.cancelled_at IS NULL
+						AND ` + blocksSlot("o") + `
+						AND o.start_time < $3
+						AND o.end_time > $2
+				)
+				AND NOT EXISTS (
+					SELECT 1
+					FROM holds h
+					WHERE h.room_id IN ` + linkedRooms("r.id") + `
+						AND h.status = 'active'
+						AND h.expires_at > $5
+						AND h.start_time < $3 AND h.end_time > $2
+				)
+				AND NOT EXISTS (
+					SELECT 1
+					FROM waitlist w
+					WHERE w.room_id IN ` + linkedRooms("r.id") + `
+						AND w.status = 'offered'
+						AND w.offer_expires_at > $5
+						AND w.start_time < $3 AND w.end_time > $2
+				)
+		ORDER BY
+				r.id
+		LIMIT $4
+	`
+
+	rows, err := s.db.Query(ctx, query, excludeRoomID, startTime, endTime, limit, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rooms := []string{}
+	for rows.Next() {
+		var roomID string
+		if err := rows.Scan(&roomID); err != nil {
+			return nil, err
+		}
+
+		rooms = append(rooms, roomID)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
 
-	return cnt > 0, nil
+	return rooms, nil
 }
 
 func NewStorage(db *pgxpool.Pool) models.ReservationStorage {
@@ -0,0 +1,204 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// blocksSlot is the condition under which a reservation of table keeps its
+// slot from others: always, except pending reservations of rooms where
+// pending requests may overlap.
+func blocksSlot(table string) string {
+	return `(` + table + `.status <> 'pending' OR COALESCE((SELECT pending_blocks FROM rooms WHERE rooms.id = ` + table + `.room_id), TRUE))`
+}
+
+// linkedRooms is the subquery of the rooms whose reservations block room:
+// the room itself, the combined room it is a part of and its own parts.
+func linkedRooms(room string) string {
+	return `(
+		SELECT ` + room + `::varchar
+		UNION SELECT parent_id FROM rooms WHERE id = ` + room + ` AND parent_id IS NOT NULL
+		UNION SELECT id FROM rooms WHERE parent_id = ` + room + `
+	)`
+}
+
+const roomColumns = `id, type, attributes, COALESCE(parent_id, ''), requires_approval, pending_blocks, capacity, updated_at`
+
+// resourcesCTE lists every known resource: the configured ones and, as
+// plain rooms, those only seen in reservations.
+const resourcesCTE = `resources AS (
+			SELECT ` + roomColumns + ` FROM rooms
+			UNION ALL
+			SELECT DISTINCT room_id, 'room', '{}'::jsonb, '', FALSE, TRUE, 0, TIMESTAMP '0001-01-01'
+			FROM reservations
+			WHERE room_id NOT IN (SELECT id FROM rooms)
+		)`
+
+func scanRoom(row pgx.Row) (models.Room, error) {
+	var room models.Room
+	err := row.Scan(
+		&room.ID,
+		&room.Type,
+		&room.Attributes,
+		&room.ParentID,
+		&room.RequiresApproval,
+		&room.PendingBlocks,
+		&room.Capacity,
+		&room.UpdatedAt,
+	)
+	return room, err
+}
+
+// GetRoom implements models.ReservationStorage. Parts are listed even when
+// the combined room itself has default settings.
+func (s *Storage) GetRoom(ctx context.Context, roomID string) (*models.Room, error) {
+	room, err := scanRoom(s.db.QueryRow(ctx, `SELECT `+roomColumns+` FROM rooms WHERE id = $1`, roomID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		room, err = models.DefaultRoom(roomID), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query(ctx, `SELECT id FROM rooms WHERE parent_id = $1 ORDER BY id`, roomID)
+	if err != nil {
+		return nil, err
+	}
+	room.Parts, err = pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, err
+	}
+
+	return &room, nil
+}
+
+// SaveRoom implements models.ReservationStorage.
+func (s *Storage) SaveRoom(ctx context.Context, room *models.Room) error {
+	query := `
+		INSERT INTO rooms(id, requires_approval, pending_blocks, parent_id, capacity, type, attributes, updated_at)
+		VALUES($1, $2, $3, NULLIF($4, ''), $5, $6, $7, $8)
+		ON CONFLICT (id) DO UPDATE
+		SET requires_approval = EXCLUDED.requires_approval,
+			pending_blocks = EXCLUDED.pending_blocks,
+			parent_id = EXCLUDED.parent_id,
+			capacity = EXCLUDED.capacity,
+			type = EXCLUDED.type,
+			attributes = EXCLUDED.attributes,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	if room.Type == "" {
+		room.Type = models.ResourceRoom
+	}
+	if room.Attributes == nil {
+		room.Attributes = map[string]string{}
+	}
+
+	room.UpdatedAt = time.Now()
+	_, err := s.db.Exec(ctx, query, room.ID, room.RequiresApproval, room.PendingBlocks, room.ParentID, room.Capacity, room.Type, room.Attributes, room.UpdatedAt)
+	return err
+}
+
+// ListResources implements models.ReservationStorage. Like in GetFreeRooms,
+// a resource is free when no reservation of it or of a linked room
+// overlaps the interval.
+func (s *Storage) ListResources(ctx context.Context, search models.ResourceQuery) ([]models.Room, error) {
+	query := `
+		WITH ` + resourcesCTE + `
+		SELECT
+				*
+		FROM
+				resources r
+		WHERE
+				r.type = $1
+				AND r.attributes @> $2::jsonb
+				AND ($3::timestamp IS NULL OR NOT EXISTS (
+					SELECT 1
+					FROM reservations o
+					WHERE o.room_id IN ` + linkedRooms("r.id") + `
+						AND o.cancelled_at IS NULL
+						AND ` + blocksSlot("o") + `
+						AND o.start_time < $4::timestamp
+						AND o.end_time > $3::timestamp
+				))
+		ORDER BY
+				r.id
+		LIMIT $5
+	`
+
+	attributes := search.Attributes
+	if attributes == nil {
+		attributes = map[string]string{}
+	}
+	var startTime, endTime any
+	if !search.StartTime.IsZero() {
+		startTime, endTime = search.StartTime, search.EndTime
+	}
+
+	rows, err := s.db.Query(ctx, query, search.Type, attributes, startTime, endTime, search.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.Room, error) {
+		return scanRoom(row)
+	})
+}
+
+// ListPending implements models.ReservationStorage.
+func (s *Storage) ListPending(ctx context.Context, roomID string) ([]models.Reservation, error) {
+	query := `
+		SELECT
+				` + reservationColumns + `
+		FROM
+				reservations
+		WHERE
+				status = 'pending'
+				AND ($1 = '' OR room_id = $1)
+		ORDER BY
+				id
+	`
+
+	rows, err := s.db.Query(ctx, query, roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	return collectReservations(rows)
+}
+
+// SetStatus implements models.ReservationStorage. Rejected reservations are
+// cancelled at the same time, so they drop out like cancelled ones.
+func (s *Storage) SetStatus(ctx context.Context, reservation *models.Reservation, status string) error {
+	eventType := models.EventReservationApproved
+	if status == models.StatusRejected {
+		eventType = models.EventReservationRejected
+	}
+
+	query := `
+		UPDATE reservations
+		SET status = $2,
+			cancelled_at = CASE WHEN $2 = 'rejected' THEN NOW() END,
+			updated_at = NOW(),
+			version = version + 1
+		WHERE id = $1
+			AND status = 'pending'
+		RETURNING ` + reservationColumns
+
+	return s.inTx(ctx, func(tx pgx.Tx) error {
+		updated, err := scanReservation(tx.QueryRow(ctx, query, reservation.ID, status))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.ErrReservationNotPending
+		}
+		if err != nil {
+			return err
+		}
+
+		*reservation = updated
+		return writeEvent(ctx, tx, eventType, reservation)
+	})
+}
@@ -0,0 +1,52 @@
+package postgresql
+
+import (
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// reservationColumns is the column list read by scanReservation.
+const reservationColumns = `id, room_id, user_id, ical_uid, start_time, end_time, version, updated_at, cancelled_at, status, checked_in_at, no_show, seats`
+
+// scanReservation reads reservationColumns followed by the columns scanned
+// into extra.
+func scanReservation(row pgx.Row, extra ...any) (models.Reservation, error) {
+	var reservation models.Reservation
+	dest := []any{
+		&reservation.ID,
+		&reservation.RoomID,
+		&reservation.UserID,
+		&reservation.ICalUID,
+		&reservation.StartTime,
+		&reservation.EndTime,
+		&reservation.Version,
+		&reservation.UpdatedAt,
+		&reservation.CancelledAt,
+		&reservation.Status,
+		&reservation.CheckedInAt,
+		&reservation.NoShow,
+		&reservation.Seats,
+	}
+	err := row.Scan(append(dest, extra...)...)
+	return reservation, err
+}
+
+func collectReservations(rows pgx.Rows) ([]models.Reservation, error) {
+	defer rows.Close()
+
+	reservations := []models.Reservation{}
+	for rows.Next() {
+		reservation, err := scanReservation(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		reservations = append(reservations, reservation)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return reservations, nil
+}
@@ -0,0 +1,224 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+	"github.com/jackc/pgx/v5"
+)
+
+const waitlistColumns = `id, room_id, user_id, start_time, end_time, auto_book, status, offer_expires_at,
+		COALESCE(reservation_id, 0), created_at`
+
+func scanWaitlistEntry(row pgx.Row) (models.WaitlistEntry, error) {
+	var entry models.WaitlistEntry
+	err := row.Scan(
+		&entry.ID,
+		&entry.RoomID,
+		&entry.UserID,
+		&entry.StartTime,
+		&entry.EndTime,
+		&entry.AutoBook,
+		&entry.Status,
+		&entry.OfferExpiresAt,
+		&entry.ReservationID,
+		&entry.CreatedAt,
+	)
+	return entry, err
+}
+
+func collectWaitlist(rows pgx.Rows) ([]models.WaitlistEntry, error) {
+	defer rows.Close()
+
+	entries := []models.WaitlistEntry{}
+	for rows.Next() {
+		entry, err := scanWaitlistEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// CreateWaitlistEntry implements models.ReservationStorage.
+func (s *Storage) CreateWaitlistEntry(ctx context.Context, entry *models.WaitlistEntry) error {
+	query := `
+		INSERT INTO waitlist(room_id, user_id, start_time, end_time, auto_book) VALUES($1, $2, $3, $4, $5)
+		RETURNING id, status, created_at
+	`
+
+	return s.db.QueryRow(ctx, query, entry.RoomID, entry.UserID, entry.StartTime, entry.EndTime, entry.AutoBook).
+		Scan(&entry.ID, &entry.Status, &entry.CreatedAt)
+}
+
+// GetWaitlistEntry implements models.ReservationStorage.
+func (s *Storage) GetWaitlistEntry(ctx context.Context, id int) (*models.WaitlistEntry, error) {
+	entry, err := scanWaitlistEntry(s.db.QueryRow(ctx, `SELECT `+waitlistColumns+` FROM waitlist WHERE id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, models.ErrWaitlistEntryNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+// ListWaitlist implements models.ReservationStorage. Empty filters match
+// every room or user.
+func (s *Storage) ListWaitlist(ctx context.Context, roomID, userID string) ([]models.WaitlistEntry, error) {
+	query := `
+		SELECT
+				` + waitlistColumns + `
+		FROM
+				waitlist
+		WHERE
+				($1 = '' OR room_id = $1)
+				AND ($2 = '' OR user_id = $2)
+				AND end_time > $3
+		ORDER BY
+				created_at, id
+	`
+
+	rows, err := s.db.Query(ctx, query, roomID, userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return collectWaitlist(rows)
+}
+
+// WithdrawWaitlistEntry implements models.ReservationStorage.
+func (s *Storage) WithdrawWaitlistEntry(ctx context.Context, id int) (*models.WaitlistEntry, error) {
+	query := `
+		UPDATE waitlist w
+		SET status = 'withdrawn'
+		FROM waitlist before
+		WHERE
+				w.id = $1
+				AND before.id = w.id
+				AND w.status IN ('waiting', 'offered')
+		RETURNING
+				before.id, before.room_id, before.user_id, before.start_time, before.end_time, before.auto_book,
+				before.status, before.offer_expires_at, COALESCE(before.reservation_id, 0), before.created_at
+	`
+
+	entry, err := scanWaitlistEntry(s.db.QueryRow(ctx, query, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, models.ErrWaitlistEntryNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+// GetWaitlistCandidates implements models.ReservationStorage. Entries for
+// intervals that already started are left out.
+func (s *Storage) GetWaitlistCandidates(ctx context.Context, roomID string, startTime time.Time, endTime time.Time) ([]models.WaitlistEntry, error) {
+	query := `
+		SELECT
+				` + waitlistColumns + `
+		FROM
+				waitlist
+		WHERE
+				room_id IN ` + linkedRooms("$1") + `
+				AND status = 'waiting'
+				AND start_time < $3 AND end_time > $2
+				AND start_time > $4
+		ORDER BY
+				created_at, id
+	`
+
+	rows, err := s.db.Query(ctx, query, roomID, startTime, endTime, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return collectWaitlist(rows)
+}
+
+// OfferWaitlistEntry implements models.ReservationStorage. The offer and
+// its event are written together.
+func (s *Storage) OfferWaitlistEntry(ctx context.Context, entry *models.WaitlistEntry, expiresAt time.Time) error {
+	query := `
+		UPDATE waitlist
+		SET status = 'offered', offer_expires_at = $2
+		WHERE id = $1 AND status = 'waiting'
+	`
+
+	return s.inTx(ctx, func(tx pgx.Tx) error {
+		res, err := tx.Exec(ctx, query, entry.ID, expiresAt)
+		if err != nil {
+			return err
+		}
+		if res.RowsAffected() == 0 {
+			return models.ErrWaitlistEntryNotFound
+		}
+
+		entry.Status = models.WaitlistOffered
+		entry.OfferExpiresAt = &expiresAt
+		return appendEvent(ctx, tx, models.ReservationEvent{
+			Type:        models.EventWaitlistOffered,
+			OccurredAt:  time.Now(),
+			Reservation: entry.Reservation(),
+			Waitlist:    entry,
+		})
+	})
+}
+
+// BookWaitlistEntry implements models.ReservationStorage.
+func (s *Storage) BookWaitlistEntry(ctx context.Context, entry *models.WaitlistEntry, reservation *models.Reservation) error {
+	query := `
+		UPDATE waitlist
+		SET status = 'booked', reservation_id = $2, offer_expires_at = NULL
+		WHERE
+				id = $1
+				AND (status = 'waiting' OR (status = 'offered' AND offer_expires_at > $3))
+	`
+
+	return s.inTx(ctx, func(tx pgx.Tx) error {
+		if err := insertReservation(ctx, tx, reservation); err != nil {
+			return err
+		}
+
+		res, err := tx.Exec(ctx, query, entry.ID, reservation.ID, time.Now())
+		if err != nil {
+			return err
+		}
+		if res.RowsAffected() == 0 {
+			return models.ErrNoWaitlistOffer
+		}
+
+		entry.Status = models.WaitlistBooked
+		entry.ReservationID = reservation.ID
+		entry.OfferExpiresAt = nil
+		return nil
+	})
+}
+
+// ExpireWaitlistOffers implements models.ReservationStorage.
+func (s *Storage) ExpireWaitlistOffers(ctx context.Context) ([]models.WaitlistEntry, error) {
+	query := `
+		UPDATE waitlist
+		SET status = 'expired'
+		WHERE status = 'offered' AND offer_expires_at <= $1
+		RETURNING ` + waitlistColumns
+
+	rows, err := s.db.Query(ctx, query, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return collectWaitlist(rows)
+}
@@ -0,0 +1,227 @@
+package postgresql
+
+import (
+	"context"
+	"time"
+
+	"github.com/Seven11Eleven/meeting_room_booking_system/internal/domain/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const deliveryColumns = `d.id, d.webhook_id, d.event_type, d.payload, d.status, d.attempts,
+		d.next_attempt_at, d.last_error, d.response_status, d.created_at, d.delivered_at`
+
+type WebhookStorage struct {
+	db *pgxpool.Pool
+}
+
+// CreateWebhook implements models.WebhookStorage.
+func (s *WebhookStorage) CreateWebhook(ctx context.Context, webhook *models.Webhook) error {
+	query := `
+		INSERT INTO webhooks(url, events, room_ids, secret) VALUES($1, $2, $3, $4)
+		RETURNING id, created_at
+	`
+
+	return s.db.QueryRow(ctx, query, webhook.URL, webhook.Events, webhook.RoomIDs, webhook.Secret).
+		Scan(&webhook.ID, &webhook.CreatedAt)
+}
+
+// ListWebhooks implements models.WebhookStorage. Secrets are not returned.
+func (s *WebhookStorage) ListWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	query := `
+		SELECT
+				id, url, events, room_ids, created_at
+		FROM
+				webhooks
+		ORDER BY
+				id
+	`
+
+	rows, err := s.db.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	webhooks := []models.Webhook{}
+	for rows.Next() {
+		var webhook models.Webhook
+		if err := rows.Scan(&webhook.ID, &webhook.URL, &webhook.Events, &webhook.RoomIDs, &webhook.CreatedAt); err != nil {
+			return nil, err
+		}
+
+		webhooks = append(webhooks, webhook)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+// DeleteWebhook implements models.WebhookStorage. Pending deliveries are
+// dropped together with the webhook.
+func (s *WebhookStorage) DeleteWebhook(ctx context.Context, id int) error {
+	res, err := s.db.Exec(ctx, `DELETE FROM webhooks WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return models.ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+// EnqueueDeliveries implements models.WebhookStorage. One delivery is queued
+// for every webhook subscribed to the event type and room.
+func (s *WebhookStorage) EnqueueDeliveries(ctx context.Context, event models.ReservationEvent, payload []byte) error {
+	query := `
+		INSERT INTO webhook_deliveries(webhook_id, event_type, payload)
+		SELECT
+				id, $1, $3
+		FROM
+				webhooks
+		WHERE
+				(cardinality(events) = 0 OR $1 = ANY(events))
+				AND (cardinality(room_ids) = 0 OR $2 = ANY(room_ids))
+	`
+
+	_, err := s.db.Exec(ctx, query, event.Type, event.Reservation.RoomID, payload)
+	return err
+}
+
+// ClaimDeliveries implements models.WebhookStorage. Claimed deliveries are
+// pushed lease into the future, so a worker that dies mid-delivery only
+// delays them, and concurrent workers skip rows claimed by each other.
+func (s *WebhookStorage) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]models.WebhookDelivery, error) {
+	query := `
+		UPDATE webhook_deliveries d
+		SET next_attempt_at = $2
+		FROM webhooks w
+		WHERE
+				w.id = d.webhook_id
+				AND d.id IN (
+					SELECT id
+					FROM webhook_deliveries
+					WHERE status = 'pending' AND next_attempt_at <= $3
+					ORDER BY next_attempt_at, id
+					LIMIT $1
+					FOR UPDATE SKIP LOCKED
+				)
+		RETURNING
+				` + deliveryColumns + `, w.url, w.secret
+	`
+
+	now := time.Now()
+	rows, err := s.db.Query(ctx, query, limit, now.Add(lease), now)
+	if err != nil {
+		return nil, err
+	}
+
+	return collectDeliveries(rows, true)
+}
+
+// CompleteDelivery implements models.WebhookStorage. It records the outcome
+// of an attempt made on a claimed delivery.
+func (s *WebhookStorage) CompleteDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = $2, attempts = $3, next_attempt_at = $4, last_error = $5, response_status = $6, delivered_at = $7
+		WHERE id = $1
+	`
+
+	_, err := s.db.Exec(ctx, query, delivery.ID, delivery.Status, delivery.Attempts, delivery.NextAttemptAt,
+		delivery.LastError, delivery.ResponseStatus, delivery.DeliveredAt)
+	return err
+}
+
+// ListDeliveries implements models.WebhookStorage, newest first.
+func (s *WebhookStorage) ListDeliveries(ctx context.Context, webhookID int, limit int) ([]models.WebhookDelivery, error) {
+	query := `
+		SELECT
+				` + deliveryColumns + `
+		FROM
+				webhook_deliveries d
+		WHERE
+				d.webhook_id = $1
+		ORDER BY
+				d.id DESC
+		LIMIT $2
+	`
+
+	rows, err := s.db.Query(ctx, query, webhookID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return collectDeliveries(rows, false)
+}
+
+// RetryDelivery implements models.WebhookStorage. The delivery is queued
+// again with a fresh attempt budget, whatever its state.
+func (s *WebhookStorage) RetryDelivery(ctx context.Context, webhookID int, deliveryID int64) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = 'pending', attempts = 0, next_attempt_at = NOW(), delivered_at = NULL
+		WHERE id = $1 AND webhook_id = $2
+	`
+
+	res, err := s.db.Exec(ctx, query, deliveryID, webhookID)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return models.ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+func collectDeliveries(rows pgx.Rows, withTarget bool) ([]models.WebhookDelivery, error) {
+	defer rows.Close()
+
+	deliveries := []models.WebhookDelivery{}
+	for rows.Next() {
+		var delivery models.WebhookDelivery
+		var payload []byte
+		dest := []any{
+			&delivery.ID,
+			&delivery.WebhookID,
+			&delivery.EventType,
+			&payload,
+			&delivery.Status,
+			&delivery.Attempts,
+			&delivery.NextAttemptAt,
+			&delivery.LastError,
+			&delivery.ResponseStatus,
+			&delivery.CreatedAt,
+			&delivery.DeliveredAt,
+		}
+		if withTarget {
+			dest = append(dest, &delivery.URL, &delivery.Secret)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		delivery.Payload = payload
+
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+func NewWebhookStorage(db *pgxpool.Pool) models.WebhookStorage {
+	return &WebhookStorage{
+		db: db,
+	}
+}
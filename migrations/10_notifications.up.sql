@@ -0,0 +1,18 @@
+CREATE TABLE notifications (
+    id BIGSERIAL PRIMARY KEY,
+    event_id BIGINT,
+    kind VARCHAR(64) NOT NULL,
+    recipient VARCHAR(255) NOT NULL,
+    reservation_id INTEGER NOT NULL,
+    payload BYTEA NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    send_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    sent_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_notifications_event_kind ON notifications(event_id, kind);
+CREATE INDEX idx_notifications_pending ON notifications(send_at, id) WHERE status = 'pending';
+CREATE INDEX idx_notifications_reservation ON notifications(reservation_id) WHERE status = 'pending';
@@ -0,0 +1,3 @@
+ALTER TABLE notifications DROP COLUMN IF EXISTS waitlist;
+
+DROP TABLE IF EXISTS waitlist;
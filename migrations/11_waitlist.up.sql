@@ -0,0 +1,17 @@
+CREATE TABLE waitlist (
+    id SERIAL PRIMARY KEY,
+    room_id VARCHAR(255) NOT NULL,
+    user_id VARCHAR(255) NOT NULL,
+    start_time TIMESTAMP NOT NULL,
+    end_time TIMESTAMP NOT NULL,
+    auto_book BOOLEAN NOT NULL DEFAULT FALSE,
+    status VARCHAR(16) NOT NULL DEFAULT 'waiting',
+    offer_expires_at TIMESTAMP,
+    reservation_id INTEGER REFERENCES reservations(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_waitlist_room_status ON waitlist(room_id, status, start_time);
+CREATE INDEX idx_waitlist_offer_expiry ON waitlist(offer_expires_at) WHERE status = 'offered';
+
+ALTER TABLE notifications ADD COLUMN waitlist BYTEA;
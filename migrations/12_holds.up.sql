@@ -0,0 +1,14 @@
+CREATE TABLE holds (
+    id SERIAL PRIMARY KEY,
+    room_id VARCHAR(255) NOT NULL,
+    user_id VARCHAR(255) NOT NULL DEFAULT '',
+    start_time TIMESTAMP NOT NULL,
+    end_time TIMESTAMP NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'active',
+    expires_at TIMESTAMP NOT NULL,
+    reservation_id INTEGER REFERENCES reservations(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_holds_room_active ON holds(room_id, start_time) WHERE status = 'active';
+CREATE INDEX idx_holds_expiry ON holds(expires_at) WHERE status = 'active';
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS rooms;
+
+DROP INDEX IF EXISTS idx_reservations_pending;
+
+ALTER TABLE reservations DROP COLUMN IF EXISTS status;
@@ -0,0 +1,12 @@
+ALTER TABLE reservations ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'approved';
+
+UPDATE reservations SET status = 'cancelled' WHERE cancelled_at IS NOT NULL;
+
+CREATE INDEX idx_reservations_pending ON reservations(room_id, start_time) WHERE status = 'pending';
+
+CREATE TABLE rooms (
+    id VARCHAR(255) PRIMARY KEY,
+    requires_approval BOOLEAN NOT NULL DEFAULT FALSE,
+    pending_blocks BOOLEAN NOT NULL DEFAULT TRUE,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
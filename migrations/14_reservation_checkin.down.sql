@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_reservations_awaiting_checkin;
+
+ALTER TABLE reservations DROP COLUMN IF EXISTS no_show;
+ALTER TABLE reservations DROP COLUMN IF EXISTS checked_in_at;
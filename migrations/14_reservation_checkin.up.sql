@@ -0,0 +1,4 @@
+ALTER TABLE reservations ADD COLUMN checked_in_at TIMESTAMP;
+ALTER TABLE reservations ADD COLUMN no_show BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX idx_reservations_awaiting_checkin ON reservations(start_time) WHERE checked_in_at IS NULL AND cancelled_at IS NULL;
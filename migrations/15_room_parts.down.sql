@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_rooms_parent;
+
+ALTER TABLE rooms DROP COLUMN IF EXISTS parent_id;
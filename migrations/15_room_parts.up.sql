@@ -0,0 +1,3 @@
+ALTER TABLE rooms ADD COLUMN parent_id VARCHAR(255);
+
+CREATE INDEX idx_rooms_parent ON rooms(parent_id) WHERE parent_id IS NOT NULL;
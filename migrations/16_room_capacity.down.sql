@@ -0,0 +1,3 @@
+ALTER TABLE reservations DROP COLUMN IF EXISTS seats;
+
+ALTER TABLE rooms DROP COLUMN IF EXISTS capacity;
@@ -0,0 +1,3 @@
+ALTER TABLE rooms ADD COLUMN capacity INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE reservations ADD COLUMN seats INTEGER NOT NULL DEFAULT 1;
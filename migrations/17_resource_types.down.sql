@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_rooms_attributes;
+DROP INDEX IF EXISTS idx_rooms_type;
+
+ALTER TABLE rooms DROP COLUMN IF EXISTS attributes;
+ALTER TABLE rooms DROP COLUMN IF EXISTS type;
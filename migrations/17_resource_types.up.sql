@@ -0,0 +1,5 @@
+ALTER TABLE rooms ADD COLUMN type VARCHAR(32) NOT NULL DEFAULT 'room';
+ALTER TABLE rooms ADD COLUMN attributes JSONB NOT NULL DEFAULT '{}';
+
+CREATE INDEX idx_rooms_type ON rooms(type);
+CREATE INDEX idx_rooms_attributes ON rooms USING GIN (attributes);
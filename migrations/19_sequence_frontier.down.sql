@@ -0,0 +1,5 @@
+ALTER TABLE outbox DROP COLUMN IF EXISTS seq, DROP COLUMN IF EXISTS xid;
+
+DROP SEQUENCE IF EXISTS outbox_seq;
+
+ALTER TABLE reservations DROP COLUMN IF EXISTS change_xid;
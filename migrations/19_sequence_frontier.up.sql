@@ -0,0 +1,14 @@
+ALTER TABLE reservations ADD COLUMN change_xid xid8;
+
+CREATE INDEX idx_reservations_change_xid ON reservations(change_xid) WHERE change_xid IS NOT NULL;
+
+CREATE SEQUENCE outbox_seq;
+
+ALTER TABLE outbox ADD COLUMN xid xid8, ADD COLUMN seq BIGINT;
+
+UPDATE outbox SET seq = id;
+
+SELECT setval('outbox_seq', COALESCE(MAX(seq), 0) + 1, false) FROM outbox;
+
+CREATE INDEX idx_outbox_unsequenced ON outbox(xid) WHERE seq IS NULL;
+CREATE INDEX idx_outbox_seq ON outbox(seq);
@@ -0,0 +1 @@
+ALTER TABLE reservations ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
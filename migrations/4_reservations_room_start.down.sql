@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_room_id_start_time;
\ No newline at end of file
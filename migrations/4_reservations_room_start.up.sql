@@ -0,0 +1 @@
+CREATE INDEX idx_room_id_start_time ON reservations(room_id, start_time, id);
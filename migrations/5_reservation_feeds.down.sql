@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_user_id;
+
+ALTER TABLE reservations
+    DROP COLUMN IF EXISTS user_id,
+    DROP COLUMN IF EXISTS updated_at,
+    DROP COLUMN IF EXISTS cancelled_at;
@@ -0,0 +1,6 @@
+ALTER TABLE reservations
+    ADD COLUMN user_id VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    ADD COLUMN cancelled_at TIMESTAMP;
+
+CREATE INDEX idx_user_id ON reservations(user_id);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_room_id_ical_uid;
+
+ALTER TABLE reservations DROP COLUMN IF EXISTS ical_uid;
@@ -0,0 +1,3 @@
+ALTER TABLE reservations ADD COLUMN ical_uid VARCHAR(255) NOT NULL DEFAULT '';
+
+CREATE INDEX idx_room_id_ical_uid ON reservations(room_id, ical_uid);
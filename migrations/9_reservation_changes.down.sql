@@ -0,0 +1,3 @@
+ALTER TABLE reservations DROP COLUMN IF EXISTS change_seq;
+
+DROP SEQUENCE IF EXISTS reservation_change_seq;
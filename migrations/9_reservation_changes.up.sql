@@ -0,0 +1,12 @@
+CREATE SEQUENCE reservation_change_seq;
+
+ALTER TABLE reservations ADD COLUMN change_seq BIGINT NOT NULL DEFAULT 0;
+
+UPDATE reservations r
+SET change_seq = c.seq
+FROM (SELECT id, ROW_NUMBER() OVER (ORDER BY updated_at, id) AS seq FROM reservations) c
+WHERE r.id = c.id;
+
+SELECT setval('reservation_change_seq', COALESCE(MAX(change_seq), 0) + 1, false) FROM reservations;
+
+CREATE INDEX idx_reservations_change_seq ON reservations(change_seq);